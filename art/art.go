@@ -0,0 +1,206 @@
+package art
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/parallel"
+	"github.com/cwseger/spotify-cli/spotify"
+	"github.com/pkg/errors"
+)
+
+// Statuses of a download
+const (
+	StatusSaved     = "saved"
+	StatusExists    = "exists"
+	StatusDuplicate = "duplicate"
+	StatusFailed    = "failed"
+)
+
+// Job is an image to save to Path
+type Job struct {
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Result is what became of a job
+type Result struct {
+	Job
+	Status string `json:"status"`
+	// Error is why the download failed
+	Error string `json:"error,omitempty"`
+}
+
+// Options -
+type Options struct {
+	// Concurrency is how many images are downloaded at once
+	Concurrency int
+	// Overwrite replaces files that already exist instead of skipping them
+	Overwrite bool
+}
+
+// Pick chooses the smallest image at least size pixels wide, or the largest
+// if none are that big. A size of 0 picks the largest. Playlist mosaics come
+// without sizes, in which case the first image is picked.
+func Pick(images []spotify.Image, size int) *spotify.Image {
+	if len(images) == 0 {
+		return nil
+	}
+	sorted := make([]spotify.Image, len(images))
+	copy(sorted, images)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Width > sorted[j].Width
+	})
+	picked := sorted[0]
+	if size > 0 {
+		for _, image := range sorted {
+			if image.Width >= size {
+				picked = image
+			}
+		}
+	}
+	return &picked
+}
+
+var placeholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// unsafe are the characters that can't be in file names on some systems
+var unsafe = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// FileName fills in a template like "{artist} - {album}.jpg" with fields.
+// Values are made safe to use in file names, while the template itself can
+// have directories in it.
+func FileName(template string, fields map[string]string) (string, error) {
+	var missing []string
+	name := placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		key := match[1 : len(match)-1]
+		value, ok := fields[key]
+		if !ok {
+			missing = append(missing, match)
+			return match
+		}
+		return strings.TrimSpace(unsafe.Replace(value))
+	})
+	if len(missing) > 0 {
+		return "", errors.Errorf("Unknown placeholder %s in %q", strings.Join(missing, ", "), template)
+	}
+	return name, nil
+}
+
+// Download saves every job's image. Each URL is only downloaded once, with
+// later jobs for it marked as duplicates, and jobs that would save different
+// images to the same path get a number added to the name. A failed download
+// doesn't stop the rest, but a cancelled context does.
+func Download(ctx context.Context, jobs []Job, options Options) ([]Result, error) {
+	results := planDownloads(jobs)
+	if err := parallel.ForEach(ctx, len(results), options.Concurrency, func(ctx context.Context, i int) error {
+		result := &results[i]
+		if result.Status != "" {
+			return nil
+		}
+		if _, err := os.Stat(result.Path); err == nil && !options.Overwrite {
+			result.Status = StatusExists
+			return nil
+		}
+		if err := download(ctx, result.URL, result.Path); err != nil {
+			result.Status, result.Error = StatusFailed, err.Error()
+			return nil
+		}
+		result.Status = StatusSaved
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// planDownloads marks the jobs for URLs that are already being downloaded as
+// duplicates and makes the paths of the rest unique
+func planDownloads(jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	paths := map[string]string{}
+	taken := map[string]bool{}
+	for i, job := range jobs {
+		results[i].Job = job
+		if path, ok := paths[job.URL]; ok {
+			results[i].Path, results[i].Status = path, StatusDuplicate
+			continue
+		}
+		path := job.Path
+		ext := filepath.Ext(path)
+		for n := 2; taken[path]; n++ {
+			path = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(job.Path, ext), n, ext)
+		}
+		taken[path] = true
+		paths[job.URL] = path
+		results[i].Path = path
+	}
+	return results
+}
+
+// download writes the image to a temporary file first, so an interrupted
+// download doesn't leave half an image behind
+func download(ctx context.Context, url, path string) error {
+	body, err := get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	part := path + ".part"
+	file, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(part)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, path)
+}
+
+// Fetch downloads an image into memory
+func Fetch(ctx context.Context, url string) ([]byte, error) {
+	body, err := get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+func get(ctx context.Context, url string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("Request failed with status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,186 @@
+package art
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// kittyChunk is the most base64 the kitty graphics protocol takes at once
+const kittyChunk = 4096
+
+// writeKitty sends the image as a PNG, split into chunks, and has the
+// terminal scale it to columns by rows cells. q=2 keeps the terminal from
+// replying, which would otherwise end up in the shell's input.
+func writeKitty(w io.Writer, img image.Image, columns, rows int) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	out := bufio.NewWriter(w)
+	for i := 0; i < len(encoded); i += kittyChunk {
+		end := i + kittyChunk
+		more := 1
+		if end >= len(encoded) {
+			end, more = len(encoded), 0
+		}
+		if i == 0 {
+			fmt.Fprintf(out, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", columns, rows, more, encoded[i:end])
+		} else {
+			fmt.Fprintf(out, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	out.WriteString("\n")
+	return out.Flush()
+}
+
+// writeITerm sends the image file as it is, since iTerm2 decodes it itself
+func writeITerm(w io.Writer, data []byte, columns, rows int) error {
+	_, err := fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a\n",
+		len(data), columns, rows, base64.StdEncoding.EncodeToString(data))
+	return err
+}
+
+// writeSixel draws the image in bands six pixels high, going over each band
+// once per color in it
+func writeSixel(w io.Writer, img image.Image) error {
+	paletted := quantize(img)
+	bounds := paletted.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "\x1bPq\"1;1;%d;%d", width, height)
+	for i, c := range paletted.Palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, percent(r), percent(g), percent(b))
+	}
+
+	sixels := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		used := map[uint8]bool{}
+		var order []uint8
+		for y := top; y < top+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				if index := paletted.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y); !used[index] {
+					used[index] = true
+					order = append(order, index)
+				}
+			}
+		}
+		for n, index := range order {
+			for x := 0; x < width; x++ {
+				var bits byte
+				for bit := 0; bit < 6 && top+bit < height; bit++ {
+					if paletted.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+top+bit) == index {
+						bits |= 1 << uint(bit)
+					}
+				}
+				sixels[x] = 63 + bits
+			}
+			fmt.Fprintf(out, "#%d", index)
+			writeRuns(out, sixels)
+			if n < len(order)-1 {
+				out.WriteByte('$')
+			}
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\\n")
+	return out.Flush()
+}
+
+// writeRuns writes sixels, with repeats of more than three shortened to !count
+func writeRuns(out *bufio.Writer, sixels []byte) {
+	for i := 0; i < len(sixels); {
+		j := i
+		for j < len(sixels) && sixels[j] == sixels[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, sixels[i])
+		} else {
+			out.Write(sixels[i:j])
+		}
+		i = j
+	}
+}
+
+// percent turns a 16-bit color channel into the 0 to 100 that sixel uses
+func percent(channel uint32) uint32 {
+	return (channel*100 + 0x7fff) / 0xffff
+}
+
+// writeBlocks draws two pixels per cell with upper half blocks, the top one in
+// the foreground color and the bottom one in the background color
+func writeBlocks(w io.Writer, img *image.RGBA, trueColor bool) error {
+	bounds := img.Bounds()
+	out := bufio.NewWriter(w)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.RGBAAt(x, y)
+			bottom := top
+			if y+1 < bounds.Max.Y {
+				bottom = img.RGBAAt(x, y+1)
+			}
+			if trueColor {
+				fmt.Fprintf(out, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			} else {
+				fmt.Fprintf(out, "\x1b[38;5;%dm\x1b[48;5;%dm▀", xterm256(top), xterm256(bottom))
+			}
+		}
+		out.WriteString("\x1b[0m\n")
+	}
+	return out.Flush()
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube in the 256 color palette
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// xterm256 finds the closest color in the cube or on the gray ramp of the 256
+// color palette
+func xterm256(c color.RGBA) int {
+	nearest := func(v int) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(v-level) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	r, g, b := int(c.R), int(c.G), int(c.B)
+	ri, gi, bi := nearest(r), nearest(g), nearest(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDistance := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// The gray ramp goes from 8 to 238 in steps of 10
+	gray := ((r+g+b)/3 - 3) / 10
+	if gray < 0 {
+		gray = 0
+	} else if gray > 23 {
+		gray = 23
+	}
+	level := 8 + gray*10
+	if distance(r, g, b, level, level, level) < cubeDistance {
+		return 232 + gray
+	}
+	return cube
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package art
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	_ "image/jpeg" // Spotify's images are JPEGs
+	_ "image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Protocols an image can be drawn in the terminal with
+const (
+	ProtocolKitty  = "kitty"
+	ProtocolITerm  = "iterm"
+	ProtocolSixel  = "sixel"
+	ProtocolBlocks = "blocks"
+)
+
+// Protocols lists every protocol, best first
+var Protocols = []string{ProtocolKitty, ProtocolITerm, ProtocolSixel, ProtocolBlocks}
+
+// Cells are assumed to be about twice as tall as they are wide, and this many
+// pixels wide for protocols that draw pixels rather than fill cells
+const cellWidth = 10
+
+// Terminal is how an image is drawn
+type Terminal struct {
+	Protocol string
+	// Columns is how wide the image is drawn, with its height following from
+	// its aspect ratio
+	Columns int
+	// TrueColor is whether blocks can use 24-bit colors rather than the
+	// 256 color palette
+	TrueColor bool
+}
+
+// Detect guesses what the terminal can draw from the environment, as asking
+// it would mean putting it in raw mode and waiting for a reply. Inside tmux or
+// screen only blocks are used, since they don't pass the other protocols on.
+func Detect() Terminal {
+	colorTerm := os.Getenv("COLORTERM")
+	terminal := Terminal{
+		Protocol:  ProtocolBlocks,
+		TrueColor: colorTerm == "truecolor" || colorTerm == "24bit",
+	}
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") {
+		return terminal
+	}
+	switch {
+	case term == "xterm-kitty" || term == "xterm-ghostty" || os.Getenv("KITTY_WINDOW_ID") != "" || program == "ghostty":
+		terminal.Protocol = ProtocolKitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		terminal.Protocol = ProtocolITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") ||
+		strings.HasPrefix(term, "yaft") || term == "contour" || program == "mlterm" || program == "contour":
+		terminal.Protocol = ProtocolSixel
+	}
+	return terminal
+}
+
+// PixelWidth is how wide an image needs to be to draw it without scaling it up
+func (t Terminal) PixelWidth() int {
+	if t.Protocol == ProtocolBlocks {
+		return t.Columns
+	}
+	return t.Columns * cellWidth
+}
+
+// Render decodes a JPEG or PNG image and draws it to w
+func (t Terminal) Render(w io.Writer, data []byte) error {
+	if t.Columns < 1 {
+		return errors.New("Image must be at least one column wide")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return errors.WithMessage(err, "Failed to decode image")
+	}
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return errors.New("Image is empty")
+	}
+	rows := (t.Columns*size.Y/size.X + 1) / 2
+	if rows < 1 {
+		rows = 1
+	}
+
+	switch t.Protocol {
+	case ProtocolKitty:
+		return writeKitty(w, img, t.Columns, rows)
+	case ProtocolITerm:
+		return writeITerm(w, data, t.Columns, rows)
+	case ProtocolSixel:
+		width := t.Columns * cellWidth
+		return writeSixel(w, resize(img, width, rows*2*cellWidth))
+	case ProtocolBlocks:
+		return writeBlocks(w, resize(img, t.Columns, rows*2), t.TrueColor)
+	}
+	return errors.Errorf("Unknown protocol %q, must be one of %s", t.Protocol, strings.Join(Protocols, ", "))
+}
+
+// resize scales an image by averaging the pixels that fall in each new pixel,
+// or by repeating pixels when scaling it up
+func resize(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*sh/height
+		y1 := bounds.Min.Y + (y+1)*sh/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*sw/width
+			x1 := bounds.Min.X + (x+1)*sw/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+pr, g+pg, b+pb, a+pa, n+1
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), uint8(a / n >> 8)})
+		}
+	}
+	return out
+}
+
+// quantize reduces an image to 256 colors, dithering to hide the banding
+func quantize(img image.Image) *image.Paletted {
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+	return paletted
+}
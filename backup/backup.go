@@ -0,0 +1,365 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+// Create takes a snapshot of the account the client is logged in to
+func Create(ctx context.Context, client spotify.Client, progress Progress) (*Snapshot, error) {
+	var snapshot Snapshot
+	steps := []struct {
+		name string
+		run  func() (int, error)
+	}{
+		{"profile", func() (int, error) {
+			out, err := client.GetCurrentUser(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.Profile = *out
+			return 1, nil
+		}},
+		{"saved tracks", func() (int, error) {
+			out, err := client.GetSavedTracks(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.SavedTracks = out.Items
+			return len(out.Items), nil
+		}},
+		{"saved albums", func() (int, error) {
+			out, err := client.GetSavedAlbums(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.SavedAlbums = out.Items
+			return len(out.Items), nil
+		}},
+		{"saved shows", func() (int, error) {
+			out, err := client.GetSavedShows(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.SavedShows = out.Items
+			return len(out.Items), nil
+		}},
+		{"saved episodes", func() (int, error) {
+			out, err := client.GetSavedEpisodes(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.SavedEpisodes = out.Items
+			return len(out.Items), nil
+		}},
+		{"saved audiobooks", func() (int, error) {
+			out, err := client.GetSavedAudiobooks(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.SavedAudiobooks = out.Items
+			return len(out.Items), nil
+		}},
+		{"followed artists", func() (int, error) {
+			out, err := client.GetFollowedArtists(ctx)
+			if err != nil {
+				return 0, err
+			}
+			snapshot.FollowedArtists = out.Inner.Items
+			return len(out.Inner.Items), nil
+		}},
+	}
+	for _, step := range steps {
+		n, err := step.run()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Failed to back up %s", step.name)
+		}
+		progress(step.name, n, n)
+	}
+
+	playlists, err := client.GetCurrentUserPlaylists(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to back up playlists")
+	}
+	for i, playlist := range playlists.Items {
+		items, err := client.GetPlaylistTracks(ctx, playlist.ID)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Failed to back up playlist %s", playlist.Name)
+		}
+		snapshot.Playlists = append(snapshot.Playlists, Playlist{
+			Playlist: playlist,
+			Owned:    playlist.Owner.ID == snapshot.Profile.ID,
+			Items:    items.Items,
+		})
+		progress("playlists", i+1, len(playlists.Items))
+	}
+
+	snapshot.Manifest = Manifest{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		User:      snapshot.Profile,
+		Counts: map[string]int{
+			"saved_tracks":     len(snapshot.SavedTracks),
+			"saved_albums":     len(snapshot.SavedAlbums),
+			"saved_shows":      len(snapshot.SavedShows),
+			"saved_episodes":   len(snapshot.SavedEpisodes),
+			"saved_audiobooks": len(snapshot.SavedAudiobooks),
+			"followed_artists": len(snapshot.FollowedArtists),
+			"playlists":        len(snapshot.Playlists),
+		},
+	}
+	for _, playlist := range snapshot.Playlists {
+		snapshot.Manifest.Playlists = append(snapshot.Manifest.Playlists, playlist.Playlist.ID)
+	}
+	return &snapshot, nil
+}
+
+// Write saves the snapshot to dest, as a gzipped tarball when dest ends in
+// .tar.gz or .tgz and as a directory of JSON files otherwise.
+func Write(snapshot *Snapshot, dest string) (err error) {
+	var archive archiveWriter
+	if isTarball(dest) {
+		archive, err = newTarWriter(dest)
+	} else {
+		archive, err = newDirWriter(dest)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := archive.Close(); err == nil && closeErr != nil {
+			err = errors.WithMessage(closeErr, "Failed to close archive")
+		}
+	}()
+
+	files := map[string]interface{}{
+		manifestFile:        snapshot.Manifest,
+		profileFile:         snapshot.Profile,
+		savedTracksFile:     snapshot.SavedTracks,
+		savedAlbumsFile:     snapshot.SavedAlbums,
+		savedShowsFile:      snapshot.SavedShows,
+		savedEpisodesFile:   snapshot.SavedEpisodes,
+		savedAudiobooksFile: snapshot.SavedAudiobooks,
+		followedArtistsFile: snapshot.FollowedArtists,
+	}
+	for _, playlist := range snapshot.Playlists {
+		files[path.Join(playlistsDir, playlist.Playlist.ID+".json")] = playlist
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to marshal %s", name)
+		}
+		if err := archive.writeFile(name, data); err != nil {
+			return errors.WithMessagef(err, "Failed to write %s", name)
+		}
+	}
+	return nil
+}
+
+// Read loads a snapshot written by Write
+func Read(src string) (*Snapshot, error) {
+	var files map[string][]byte
+	var err error
+	if isTarball(src) {
+		files, err = readTarball(src)
+	} else {
+		files, err = readDir(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := unmarshalFile(files, manifestFile, &snapshot.Manifest); err != nil {
+		return nil, err
+	}
+	if snapshot.Manifest.Version > Version {
+		return nil, errors.Errorf("Archive version %d is newer than the supported version %d", snapshot.Manifest.Version, Version)
+	}
+
+	for name, v := range map[string]interface{}{
+		profileFile:         &snapshot.Profile,
+		savedTracksFile:     &snapshot.SavedTracks,
+		savedAlbumsFile:     &snapshot.SavedAlbums,
+		savedShowsFile:      &snapshot.SavedShows,
+		savedEpisodesFile:   &snapshot.SavedEpisodes,
+		savedAudiobooksFile: &snapshot.SavedAudiobooks,
+		followedArtistsFile: &snapshot.FollowedArtists,
+	} {
+		if err := unmarshalFile(files, name, v); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range snapshot.Manifest.Playlists {
+		var playlist Playlist
+		if err := unmarshalFile(files, path.Join(playlistsDir, id+".json"), &playlist); err != nil {
+			return nil, err
+		}
+		snapshot.Playlists = append(snapshot.Playlists, playlist)
+	}
+	return &snapshot, nil
+}
+
+func unmarshalFile(files map[string][]byte, name string, v interface{}) error {
+	data, ok := files[name]
+	if !ok {
+		return errors.Errorf("Archive is missing %s", name)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.WithMessagef(err, "Failed to unmarshal %s", name)
+	}
+	return nil
+}
+
+func isTarball(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+type archiveWriter interface {
+	writeFile(name string, data []byte) error
+	Close() error
+}
+
+type dirWriter struct {
+	root string
+}
+
+func newDirWriter(root string) (*dirWriter, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, errors.WithMessage(err, "Failed to create backup directory")
+	}
+	return &dirWriter{root: root}, nil
+}
+
+func (w *dirWriter) writeFile(name string, data []byte) error {
+	dest := filepath.Join(w.root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dest, data, 0644)
+}
+
+func (w *dirWriter) Close() error {
+	return nil
+}
+
+type tarWriter struct {
+	file *os.File
+	gzip *gzip.Writer
+	tar  *tar.Writer
+}
+
+func newTarWriter(dest string) (*tarWriter, error) {
+	file, err := os.Create(dest)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to create backup file")
+	}
+	gz := gzip.NewWriter(file)
+	return &tarWriter{
+		file: file,
+		gzip: gz,
+		tar:  tar.NewWriter(gz),
+	}, nil
+}
+
+func (w *tarWriter) writeFile(name string, data []byte) error {
+	if err := w.tar.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := w.tar.Write(data)
+	return err
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.tar.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.gzip.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func readDir(root string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read backup directory")
+	}
+	return files, nil
+}
+
+func readTarball(src string) (map[string][]byte, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to open backup file")
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to decompress backup file")
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to read backup file")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.WithMessage(err, "Failed to read backup file")
+		}
+		files[path.Clean(header.Name)] = data
+	}
+}
@@ -0,0 +1,66 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+)
+
+// Version is the archive format written by Write. Read refuses archives from
+// newer versions.
+const Version = 1
+
+// Files in an archive. Each playlist is stored on its own as playlists/<id>.json.
+const (
+	manifestFile        = "manifest.json"
+	profileFile         = "profile.json"
+	savedTracksFile     = "saved_tracks.json"
+	savedAlbumsFile     = "saved_albums.json"
+	savedShowsFile      = "saved_shows.json"
+	savedEpisodesFile   = "saved_episodes.json"
+	savedAudiobooksFile = "saved_audiobooks.json"
+	followedArtistsFile = "followed_artists.json"
+	playlistsDir        = "playlists"
+)
+
+// Manifest describes an archive
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	User      spotify.User   `json:"user"`
+	Counts    map[string]int `json:"counts"`
+	// Playlists lists the playlist IDs in the order the user had them
+	Playlists []string `json:"playlists"`
+}
+
+// Playlist is a playlist along with every item in it
+type Playlist struct {
+	Playlist spotify.Playlist        `json:"playlist"`
+	Owned    bool                    `json:"owned"`
+	Items    []spotify.PlaylistTrack `json:"items"`
+}
+
+// Snapshot is the complete state of an account
+type Snapshot struct {
+	Manifest        Manifest
+	Profile         spotify.User
+	SavedTracks     []spotify.SavedTrack
+	SavedAlbums     []spotify.SavedAlbum
+	SavedShows      []spotify.SavedShow
+	SavedEpisodes   []spotify.SavedEpisode
+	SavedAudiobooks []spotify.Audiobook
+	FollowedArtists []spotify.Artist
+	Playlists       []Playlist
+}
+
+// RestoreState records how far a restore got so an interrupted one can pick
+// up where it left off.
+type RestoreState struct {
+	UserID    string          `json:"user_id"`
+	Completed map[string]bool `json:"completed"`
+	// Playlists maps the ID of each backed up playlist to the one it was restored to
+	Playlists map[string]string `json:"playlists"`
+}
+
+// Progress is called as backup and restore work through each step
+type Progress func(step string, done, total int)
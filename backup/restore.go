@@ -0,0 +1,349 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+// restoreBatchSize is how many items are saved or followed between progress reports
+const restoreBatchSize = 50
+
+// Restore replays a snapshot into the account the client is logged in to,
+// which doesn't have to be the one it was taken from. Only what is missing
+// is added, so running it twice changes nothing the second time. Progress
+// is recorded in the file at statePath after every step so an interrupted
+// restore can be resumed by running it again. The file is removed once the
+// restore finishes.
+func Restore(ctx context.Context, client spotify.Client, snapshot *Snapshot, statePath string, progress Progress) error {
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	state, err := loadState(statePath, user.ID)
+	if err != nil {
+		return err
+	}
+
+	libraries := []struct {
+		step        string
+		libraryType spotify.LibraryType
+		ids         []string
+	}{
+		{"saved tracks", spotify.LibraryTracks, savedTrackIDs(snapshot.SavedTracks)},
+		{"saved albums", spotify.LibraryAlbums, savedAlbumIDs(snapshot.SavedAlbums)},
+		{"saved shows", spotify.LibraryShows, savedShowIDs(snapshot.SavedShows)},
+		{"saved episodes", spotify.LibraryEpisodes, savedEpisodeIDs(snapshot.SavedEpisodes)},
+		{"saved audiobooks", spotify.LibraryAudiobooks, audiobookIDs(snapshot.SavedAudiobooks)},
+	}
+	for _, library := range libraries {
+		if state.Completed[library.step] {
+			progress(library.step, len(library.ids), len(library.ids))
+			continue
+		}
+		if err := restoreLibrary(ctx, client, library.step, library.libraryType, library.ids, progress); err != nil {
+			return errors.WithMessagef(err, "Failed to restore %s", library.step)
+		}
+		if err := state.complete(statePath, library.step); err != nil {
+			return err
+		}
+	}
+
+	if !state.Completed["followed artists"] {
+		if err := restoreFollowedArtists(ctx, client, snapshot.FollowedArtists, progress); err != nil {
+			return errors.WithMessage(err, "Failed to restore followed artists")
+		}
+		if err := state.complete(statePath, "followed artists"); err != nil {
+			return err
+		}
+	}
+
+	current, err := client.GetCurrentUserPlaylists(ctx)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, playlist := range current.Items {
+		existing[playlist.ID] = true
+	}
+	owned := ownedPlaylists(current.Items, user, snapshot, state)
+	for i, playlist := range snapshot.Playlists {
+		step := "playlist " + playlist.Playlist.ID
+		if !state.Completed[step] {
+			if err := restorePlaylist(ctx, client, user, snapshot, playlist, existing, owned, state, statePath); err != nil {
+				return errors.WithMessagef(err, "Failed to restore playlist %s", playlist.Playlist.Name)
+			}
+			if err := state.complete(statePath, step); err != nil {
+				return err
+			}
+		}
+		progress("playlists", i+1, len(snapshot.Playlists))
+	}
+
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "Failed to remove restore state")
+	}
+	return nil
+}
+
+func restoreLibrary(ctx context.Context, client spotify.Client, step string, libraryType spotify.LibraryType, ids []string, progress Progress) error {
+	var missing []string
+	checked := 0
+	for _, batch := range spotify.Chunk(ids, restoreBatchSize) {
+		saved, err := client.CheckLibrary(ctx, libraryType, batch)
+		if err != nil {
+			return err
+		}
+		for i, id := range batch {
+			if !saved[i] {
+				missing = append(missing, id)
+			}
+		}
+		checked += len(batch)
+		progress("checking "+step, checked, len(ids))
+	}
+
+	// The library is ordered by when items were saved, so the oldest go in first
+	reverse(missing)
+	done := 0
+	for _, ids := range spotify.Chunk(missing, restoreBatchSize) {
+		if err := client.SaveToLibrary(ctx, libraryType, ids); err != nil {
+			return err
+		}
+		done += len(ids)
+		progress(step, done, len(missing))
+	}
+	if len(missing) == 0 {
+		progress(step, 0, 0)
+	}
+	return nil
+}
+
+func restoreFollowedArtists(ctx context.Context, client spotify.Client, artists []spotify.Artist, progress Progress) error {
+	current, err := client.GetFollowedArtists(ctx)
+	if err != nil {
+		return err
+	}
+	following := map[string]bool{}
+	for _, artist := range current.Inner.Items {
+		following[artist.ID] = true
+	}
+	var missing []string
+	for _, artist := range artists {
+		if !following[artist.ID] {
+			missing = append(missing, artist.ID)
+		}
+	}
+
+	done := 0
+	for _, ids := range spotify.Chunk(missing, restoreBatchSize) {
+		if err := client.Follow(ctx, "artist", ids); err != nil {
+			return err
+		}
+		done += len(ids)
+		progress("followed artists", done, len(missing))
+	}
+	if len(missing) == 0 {
+		progress("followed artists", 0, 0)
+	}
+	return nil
+}
+
+// restorePlaylist follows playlists owned by someone else and recreates the
+// ones the user owned. A playlist is reused rather than created again when
+// restoring into the account it came from, when an earlier, interrupted run
+// already created it, or when the user has one with the same name and
+// description, as an earlier restore into another account leaves behind.
+func restorePlaylist(ctx context.Context, client spotify.Client, user *spotify.User, snapshot *Snapshot, playlist Playlist, existing map[string]bool, owned map[string][]string, state *RestoreState, statePath string) error {
+	public := playlist.Playlist.Public == nil || *playlist.Playlist.Public
+	if !playlist.Owned {
+		if existing[playlist.Playlist.ID] {
+			return nil
+		}
+		return client.FollowPlaylist(ctx, playlist.Playlist.ID, public)
+	}
+
+	targetID := state.Playlists[playlist.Playlist.ID]
+	if targetID == "" && user.ID == snapshot.Manifest.User.ID && existing[playlist.Playlist.ID] {
+		targetID = playlist.Playlist.ID
+	}
+	if targetID == "" {
+		key := playlistKey(playlist.Playlist)
+		if ids := owned[key]; len(ids) > 0 {
+			targetID, owned[key] = ids[0], ids[1:]
+		}
+	}
+	if targetID == "" {
+		created, err := client.CreatePlaylist(ctx, user.ID, &spotify.CreatePlaylistInput{
+			Name:        playlist.Playlist.Name,
+			Description: playlist.Playlist.Description,
+			Public:      public,
+		})
+		if err != nil {
+			return err
+		}
+		targetID = created.ID
+		state.Playlists[playlist.Playlist.ID] = targetID
+		if err := state.save(statePath); err != nil {
+			return err
+		}
+	}
+
+	var uris []string
+	for _, item := range playlist.Items {
+		// Local files can't be added through the API
+		if item.Track != nil && item.Track.URI != "" && !strings.HasPrefix(item.Track.URI, "spotify:local:") {
+			uris = append(uris, item.Track.URI)
+		}
+	}
+
+	current, err := client.GetPlaylistTracks(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	if sameURIs(current.Items, uris) {
+		return nil
+	}
+	return client.ReplacePlaylistTracks(ctx, targetID, uris)
+}
+
+// ownedPlaylists lists the IDs of the user's own playlists by name and
+// description, leaving out those already taken by a playlist in the snapshot:
+// ones an interrupted run restored to, and the snapshot's own when restoring
+// into the account it came from.
+func ownedPlaylists(playlists []spotify.Playlist, user *spotify.User, snapshot *Snapshot, state *RestoreState) map[string][]string {
+	taken := map[string]bool{}
+	for _, id := range state.Playlists {
+		taken[id] = true
+	}
+	if user.ID == snapshot.Manifest.User.ID {
+		for _, playlist := range snapshot.Playlists {
+			taken[playlist.Playlist.ID] = true
+		}
+	}
+	owned := map[string][]string{}
+	for _, playlist := range playlists {
+		if playlist.Owner.ID == user.ID && !taken[playlist.ID] {
+			key := playlistKey(playlist)
+			owned[key] = append(owned[key], playlist.ID)
+		}
+	}
+	return owned
+}
+
+func playlistKey(playlist spotify.Playlist) string {
+	return playlist.Name + "\x00" + playlist.Description
+}
+
+func loadState(statePath, userID string) (*RestoreState, error) {
+	state := &RestoreState{
+		UserID:    userID,
+		Completed: map[string]bool{},
+		Playlists: map[string]string{},
+	}
+	data, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read restore state")
+	}
+
+	var saved RestoreState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errors.WithMessage(err, "Failed to unmarshal restore state")
+	}
+	// A restore into another account starts over
+	if saved.UserID != userID {
+		return state, nil
+	}
+	if saved.Completed != nil {
+		state.Completed = saved.Completed
+	}
+	if saved.Playlists != nil {
+		state.Playlists = saved.Playlists
+	}
+	return state, nil
+}
+
+func (s *RestoreState) complete(statePath, step string) error {
+	s.Completed[step] = true
+	return s.save(statePath)
+}
+
+func (s *RestoreState) save(statePath string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal restore state")
+	}
+	if err := ioutil.WriteFile(statePath, data, 0644); err != nil {
+		return errors.WithMessage(err, "Failed to write restore state")
+	}
+	return nil
+}
+
+func sameURIs(items []spotify.PlaylistTrack, uris []string) bool {
+	if len(items) != len(uris) {
+		return false
+	}
+	for i, item := range items {
+		if item.Track == nil || item.Track.URI != uris[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func savedTrackIDs(items []spotify.SavedTrack) []string {
+	var ids []string
+	for _, item := range items {
+		if item.Track.ID != "" {
+			ids = append(ids, item.Track.ID)
+		}
+	}
+	return ids
+}
+
+func savedAlbumIDs(items []spotify.SavedAlbum) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Album.ID)
+	}
+	return ids
+}
+
+func savedShowIDs(items []spotify.SavedShow) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Show.ID)
+	}
+	return ids
+}
+
+func savedEpisodeIDs(items []spotify.SavedEpisode) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Episode.ID)
+	}
+	return ids
+}
+
+func audiobookIDs(items []spotify.Audiobook) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func reverse(ids []string) {
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/cwseger/spotify-cli/spotify"
 	cobra "github.com/spf13/cobra"
 )
 
@@ -15,7 +14,7 @@ var albumCommands = []*cobra.Command{
 		Args:    cobra.MinimumNArgs(1),
 		Example: "spotify-cli Control",
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
 				fmt.Println("Failed to create new spotify client")
 				return
@@ -23,7 +22,9 @@ var albumCommands = []*cobra.Command{
 			out, err := spotifyClient.GetAlbum(cmd.Context(), strings.Join(args, " "))
 			if err != nil {
 				fmt.Println("Failed to get album:", err)
+				return
 			}
+			showArt(cmd, out.Images)
 			fmt.Println("Name", "|", "Artist", "|", "Popularity")
 			fmt.Println(out.Name, "|", out.Artists[0].Name, "|", out.Popularity)
 		},
@@ -34,7 +35,7 @@ var albumCommands = []*cobra.Command{
 		Example: "spotify-cli album-tracks TODO",
 		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
 				fmt.Println("Failed to create new spotify client")
 				return
@@ -43,6 +44,7 @@ var albumCommands = []*cobra.Command{
 			out, err := spotifyClient.GetAlbumTracks(cmd.Context(), strings.Join(args, " "))
 			if err != nil {
 				fmt.Println("Failed to get album tracks:", err)
+				return
 			}
 
 			fmt.Println("Track Number", "|", "Name")
@@ -52,3 +54,7 @@ var albumCommands = []*cobra.Command{
 		},
 	},
 }
+
+func init() {
+	addArtFlags(albumCommands[0])
+}
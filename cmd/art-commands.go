@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/art"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var artCommand = &cobra.Command{
+	Use:   "art <album|artist|playlist|show> <item...>",
+	Short: "Download cover art and artist images",
+	Long: "Saves the image of an album, artist, playlist or show, picking the smallest one at least --size pixels wide. " +
+		"With --all, saves the cover of every album in an artist's discography or on a playlist instead, a few at a time, " +
+		"downloading each image only once.\n\n" +
+		"--name is a template for the file names, which can use {name} and {id} for every item, {artist}, {album} and {year} " +
+		"for albums, {artist} for artists, {playlist} and {owner} for playlists, {show} and {publisher} for shows, " +
+		"and {width} and {height}. It defaults to \"{artist} - {album}.jpg\" for albums and the name of the item otherwise. " +
+		"Files that already exist are skipped unless --overwrite is given.",
+	Example: "spotify-cli art album \"Random Access Memories\"\n" +
+		"spotify-cli art artist Khruangbin --all --dir covers --name \"{year} - {album}.jpg\"\n" +
+		"spotify-cli art playlist spotify:playlist:37i9dQZF1DXcBWIGoYBM5M --all --size 300",
+	Args: artArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		size, _ := cmd.Flags().GetInt("size")
+		dir, _ := cmd.Flags().GetString("dir")
+		template, _ := cmd.Flags().GetString("name")
+		all, _ := cmd.Flags().GetBool("all")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		if all && args[0] != "artist" && args[0] != "playlist" {
+			fmt.Println("--all only works with artists and playlists")
+			return
+		}
+
+		// Private playlists need the user's token, everything else works without one
+		spotifyClient, err := newUserClient(cmd)
+		if err == spotify.ErrNotLoggedIn {
+			spotifyClient, err = newClient(cmd)
+		}
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		items, err := artItems(cmd.Context(), spotifyClient, args[0], strings.Join(args[1:], " "), all)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		var jobs []art.Job
+		for _, item := range items {
+			image := art.Pick(item.images, size)
+			if image == nil {
+				continue
+			}
+			item.fields["width"] = strconv.Itoa(image.Width)
+			item.fields["height"] = strconv.Itoa(image.Height)
+			itemTemplate := template
+			if itemTemplate == "" {
+				itemTemplate = item.template
+			}
+			name, err := art.FileName(itemTemplate, item.fields)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			jobs = append(jobs, art.Job{URL: image.URL, Path: filepath.Join(dir, name), Width: image.Width, Height: image.Height})
+		}
+		if len(jobs) == 0 {
+			fmt.Println("No images found")
+			return
+		}
+
+		results, err := art.Download(cmd.Context(), jobs, art.Options{Concurrency: concurrency, Overwrite: overwrite})
+		if err != nil {
+			fmt.Println("Failed to download images:", err)
+			return
+		}
+		var rows [][]string
+		counts := map[string]int{}
+		for _, result := range results {
+			status := result.Status
+			if result.Error != "" {
+				status += ": " + result.Error
+			}
+			counts[result.Status]++
+			rows = append(rows, []string{result.Path, fmt.Sprintf("%dx%d", result.Width, result.Height), status, result.URL})
+		}
+		printOutput(cmd, []string{"File", "Size", "Status", "URL"}, rows, results)
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Printf("Saved %d, %d already existed, %d duplicates, %d failed\n",
+				counts[art.StatusSaved], counts[art.StatusExists], counts[art.StatusDuplicate], counts[art.StatusFailed])
+		}
+	},
+}
+
+// artItem is something with images, with the values its file name template can use
+type artItem struct {
+	images   []spotify.Image
+	fields   map[string]string
+	template string
+}
+
+// artItems looks up the item or, with all, the albums of the artist or playlist
+func artItems(ctx context.Context, spotifyClient spotify.Client, artType, item string, all bool) ([]artItem, error) {
+	switch artType {
+	case "album":
+		album, err := spotifyClient.GetAlbum(ctx, item)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get album: %v", err)
+		}
+		return []artItem{albumArt(album.Album)}, nil
+
+	case "artist":
+		id, err := spotifyClient.ResolveID(ctx, item, "artist")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find artist: %v", err)
+		}
+		if all {
+			out, err := spotifyClient.GetArtistDiscography(ctx, id, "")
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get discography: %v", err)
+			}
+			var items []artItem
+			for _, album := range out.Albums {
+				items = append(items, albumArt(album))
+			}
+			return items, nil
+		}
+		out, err := spotifyClient.GetSeveralArtists(ctx, []string{id})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get artist: %v", err)
+		}
+		if len(out.NotFound) > 0 {
+			return nil, fmt.Errorf("Couldn't find artist %s", item)
+		}
+		artist := out.Artists[0]
+		return []artItem{{
+			images:   artist.Images,
+			fields:   map[string]string{"name": artist.Name, "id": artist.ID, "artist": artist.Name},
+			template: "{artist}.jpg",
+		}}, nil
+
+	case "playlist":
+		if all {
+			out, err := spotifyClient.GetPlaylistTracks(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get playlist tracks: %v", err)
+			}
+			var items []artItem
+			for _, playlistTrack := range out.Items {
+				if playlistTrack.Track != nil && len(playlistTrack.Track.Album.Images) > 0 {
+					items = append(items, albumArt(playlistTrack.Track.Album))
+				}
+			}
+			return items, nil
+		}
+		playlist, err := spotifyClient.GetPlaylist(ctx, item)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get playlist: %v", err)
+		}
+		return []artItem{{
+			images:   playlist.Images,
+			fields:   map[string]string{"name": playlist.Name, "id": playlist.ID, "playlist": playlist.Name, "owner": playlist.Owner.DisplayName},
+			template: "{playlist}.jpg",
+		}}, nil
+
+	case "show":
+		show, err := spotifyClient.GetShow(ctx, item, "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get show: %v", err)
+		}
+		return []artItem{{
+			images:   show.Images,
+			fields:   map[string]string{"name": show.Name, "id": show.ID, "show": show.Name, "publisher": show.Publisher},
+			template: "{show}.jpg",
+		}}, nil
+	}
+	return nil, fmt.Errorf("Can't get art for %s", artType)
+}
+
+func albumArt(album spotify.Album) artItem {
+	artist := ""
+	if len(album.Artists) > 0 {
+		artist = album.Artists[0].Name
+	}
+	year := album.ReleaseDate
+	if len(year) > 4 {
+		year = year[:4]
+	}
+	return artItem{
+		images:   album.Images,
+		fields:   map[string]string{"name": album.Name, "id": album.ID, "artist": artist, "album": album.Name, "year": year},
+		template: "{artist} - {album}.jpg",
+	}
+}
+
+// addArtFlags adds --art and --art-width to a command that can show an image
+func addArtFlags(cmd *cobra.Command) {
+	cmd.Flags().String("art", "", "Show the image in the terminal with auto, kitty, iterm, sixel or blocks")
+	cmd.Flags().Lookup("art").NoOptDefVal = "auto"
+	cmd.Flags().Int("art-width", 32, "Width of the image in columns")
+}
+
+// showArt draws the best fitting of the images in the terminal when --art is
+// given. Detecting the protocol only happens when printing text to a terminal,
+// so piping the output doesn't fill it with escape codes.
+func showArt(cmd *cobra.Command, images []spotify.Image) {
+	protocol, _ := cmd.Flags().GetString("art")
+	columns, _ := cmd.Flags().GetInt("art-width")
+	if protocol == "" {
+		return
+	}
+	if format, _ := cmd.Flags().GetString("output"); format != outputText {
+		return
+	}
+
+	terminal := art.Detect()
+	if protocol == "auto" {
+		if !isTerminal(os.Stdout) {
+			return
+		}
+	} else {
+		terminal.Protocol = protocol
+	}
+	terminal.Columns = columns
+
+	image := art.Pick(images, terminal.PixelWidth())
+	if image == nil {
+		return
+	}
+	data, err := art.Fetch(cmd.Context(), image.URL)
+	if err != nil {
+		fmt.Println("Failed to download image:", err)
+		return
+	}
+	if err := terminal.Render(os.Stdout, data); err != nil {
+		fmt.Println("Failed to show image:", err)
+	}
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func artArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("Must provide album, artist, playlist or show and an item")
+	}
+	switch args[0] {
+	case "album", "artist", "playlist", "show":
+		return nil
+	}
+	return fmt.Errorf("Can only get art for album, artist, playlist or show, not %q", args[0])
+}
+
+func init() {
+	artCommand.Flags().Int("size", 640, "Smallest width in pixels to pick an image of, 0 for the largest")
+	artCommand.Flags().String("dir", ".", "Directory to save images in")
+	artCommand.Flags().String("name", "", "File name template, such as \"{artist} - {album}.jpg\"")
+	artCommand.Flags().Bool("all", false, "Save the cover of every album of the artist or on the playlist")
+	artCommand.Flags().Int("concurrency", 4, "How many images to download at once")
+	artCommand.Flags().Bool("overwrite", false, "Replace files that already exist")
+}
@@ -3,8 +3,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	"github.com/cwseger/spotify-cli/graph"
 	"github.com/cwseger/spotify-cli/spotify"
 	cobra "github.com/spf13/cobra"
 )
@@ -21,7 +24,7 @@ var artistCommands = []*cobra.Command{
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
 				fmt.Println(err, "Failed to create new spotify client")
 				return
@@ -30,10 +33,30 @@ var artistCommands = []*cobra.Command{
 			out, err := spotifyClient.GetArtist(cmd.Context(), strings.Join(args, ""))
 			if err != nil {
 				fmt.Println(err, "Failed to get artist")
+				return
+			}
+			if len(out.Inner.Artists) == 0 {
+				fmt.Println("No artist found")
+				return
+			}
+			artist := out.Inner.Artists[0]
+			showArt(cmd, artist.Images)
+			fmt.Println("Name:", artist.Name)
+			fmt.Println("Popularity:", artist.Popularity)
+			fmt.Println("Followers:", artist.Followers.Total)
+
+			if follow, _ := cmd.Flags().GetBool("follow"); follow {
+				userClient, err := newUserClient(cmd)
+				if err != nil {
+					fmt.Println(err, "Failed to create new spotify client")
+					return
+				}
+				if err := userClient.Follow(cmd.Context(), "artist", []string{artist.ID}); err != nil {
+					fmt.Println(err, "Failed to follow artist")
+					return
+				}
+				fmt.Println("Followed", artist.Name)
 			}
-			fmt.Println("Name:", out.Inner.Artists[0].Name)
-			fmt.Println("Popularity:", out.Inner.Artists[0].Popularity)
-			fmt.Println("Followers:", out.Inner.Artists[0].Followers.Total)
 		},
 	},
 	{
@@ -47,7 +70,7 @@ var artistCommands = []*cobra.Command{
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
 				fmt.Println(err, "Failed to create new spotify client")
 				return
@@ -56,6 +79,7 @@ var artistCommands = []*cobra.Command{
 			out, err := spotifyClient.GetArtistAlbums(cmd.Context(), strings.Join(args, ""))
 			if err != nil {
 				fmt.Println(err, "Failed to get artist")
+				return
 			}
 			for i := range out.Albums {
 				fmt.Println("Name:", out.Albums[i].Name)
@@ -63,3 +87,192 @@ var artistCommands = []*cobra.Command{
 		},
 	},
 }
+
+var artistSubcommands = []*cobra.Command{
+	{
+		Use:     "top-tracks <artist>",
+		Short:   "Get an artist's most popular tracks in a market",
+		Example: "spotify-cli artist top-tracks The Black Keys --market GB",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Top tracks are ranked per country, and only a user token has one
+			// to fall back on
+			market, _ := cmd.Flags().GetString("market")
+			if market == "" {
+				fmt.Println("Top tracks are ranked per country, so give one with --market or set a default with `spotify-cli config set market <country>`")
+				return
+			}
+			newTopTracksClient := newClient
+			if market == spotify.FromToken {
+				newTopTracksClient = newUserClient
+			}
+			spotifyClient, err := newTopTracksClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			out, err := spotifyClient.GetArtistTopTracks(cmd.Context(), strings.Join(args, " "), "")
+			if err != nil {
+				fmt.Println("Failed to get top tracks:", err)
+				return
+			}
+			var rows [][]string
+			for i, track := range out.Tracks {
+				rows = append(rows, []string{strconv.Itoa(i + 1), track.Name, track.Album.Name, formatDuration(track.DurationMS), strconv.Itoa(track.Popularity), track.URI})
+			}
+			printOutput(cmd, []string{"Rank", "Name", "Album", "Duration", "Popularity", "URI"}, rows, out.Tracks)
+		},
+	},
+	{
+		Use:     "related <artist>",
+		Short:   "Get artists similar to an artist",
+		Example: "spotify-cli artist related Khruangbin",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			out, err := spotifyClient.GetRelatedArtists(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				fmt.Println("Failed to get related artists:", err)
+				return
+			}
+			var rows [][]string
+			for _, artist := range out.Artists {
+				rows = append(rows, []string{artist.Name, strings.Join(artist.Genres, ", "), strconv.Itoa(artist.Popularity), strconv.Itoa(artist.Followers.Total), artist.URI})
+			}
+			printOutput(cmd, []string{"Name", "Genres", "Popularity", "Followers", "URI"}, rows, out.Artists)
+		},
+	},
+	{
+		Use:     "discography <artist>",
+		Short:   "List every album, single, compilation and appearance of an artist by release date",
+		Example: "spotify-cli artist discography The Black Keys\nspotify-cli artist discography Khruangbin --group album --group single --market US",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			market, _ := cmd.Flags().GetString("market")
+			groups, _ := cmd.Flags().GetStringSlice("group")
+			include := map[string]bool{}
+			for _, group := range groups {
+				if !isAlbumGroup(group) {
+					fmt.Println("Group must be one of", strings.Join(spotify.AlbumGroups, ", "))
+					return
+				}
+				include[group] = true
+			}
+
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			out, err := spotifyClient.GetArtistDiscography(cmd.Context(), strings.Join(args, " "), market)
+			if err != nil {
+				fmt.Println("Failed to get discography:", err)
+				return
+			}
+
+			var albums []spotify.Album
+			var rows [][]string
+			for _, album := range out.Albums {
+				if len(include) > 0 && !include[album.AlbumGroup] {
+					continue
+				}
+				albums = append(albums, album)
+				rows = append(rows, []string{album.ReleaseDate, album.Name, album.AlbumGroup, strconv.Itoa(album.TotalTracks), artistNames(album.Artists), album.URI})
+			}
+			printOutput(cmd, []string{"Released", "Name", "Group", "Tracks", "Artist", "URI"}, rows, albums)
+		},
+	},
+	{
+		Use:   "graph <artist>",
+		Short: "Map the artists related to an artist, a few hops out",
+		Long: "Follows related artists breadth first up to --depth hops away and writes the graph with each artist's genres, popularity and followers, " +
+			"as Graphviz DOT, GraphML or JSON in node-link format. Each hop multiplies the number of requests by about 20.",
+		Example: "spotify-cli artist graph Khruangbin --depth 2 > khruangbin.dot\nspotify-cli artist graph \"The Black Keys\" --depth 3 --format graphml --file black-keys.graphml",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			depth, _ := cmd.Flags().GetInt("depth")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			maxArtists, _ := cmd.Flags().GetInt("max-artists")
+			format, _ := cmd.Flags().GetString("format")
+			path, _ := cmd.Flags().GetString("file")
+			if depth < 1 {
+				fmt.Println("Depth must be at least 1")
+				return
+			}
+			switch format {
+			case graph.FormatDOT, graph.FormatGraphML, graph.FormatJSON:
+			default:
+				fmt.Println("Format must be dot, graphml or json")
+				return
+			}
+
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			artistID, err := spotifyClient.ResolveID(cmd.Context(), strings.Join(args, " "), "artist")
+			if err != nil {
+				fmt.Println("Failed to find artist:", err)
+				return
+			}
+			g, err := graph.Explore(cmd.Context(), spotifyClient, artistID, graph.Options{
+				Depth:       depth,
+				Concurrency: concurrency,
+				MaxArtists:  maxArtists,
+			})
+			if err != nil {
+				fmt.Println("Failed to map related artists:", err)
+				return
+			}
+
+			if path == "" {
+				if err := graph.Write(os.Stdout, g, format); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
+			file, err := os.Create(path)
+			if err != nil {
+				fmt.Println("Failed to create graph file:", err)
+				return
+			}
+			if err := graph.Write(file, g, format); err != nil {
+				file.Close()
+				fmt.Println(err)
+				return
+			}
+			if err := file.Close(); err != nil {
+				fmt.Println("Failed to write graph:", err)
+				return
+			}
+			fmt.Println(fmt.Sprintf("Wrote %d artists and %d links to %s", len(g.Nodes), len(g.Links), path))
+		},
+	},
+}
+
+func init() {
+	artistCommands[0].Flags().Bool("follow", false, "Follow the artist")
+	addArtFlags(artistCommands[0])
+
+	artistSubcommands[2].Flags().StringSlice("group", nil, "Only list these groups: "+strings.Join(spotify.AlbumGroups, ", ")+" (default all)")
+	artistSubcommands[3].Flags().Int("depth", 2, "How many hops of related artists to follow")
+	artistSubcommands[3].Flags().Int("concurrency", 4, "How many requests to make at once")
+	artistSubcommands[3].Flags().Int("max-artists", 500, "Stop adding artists after this many, 0 for no limit")
+	artistSubcommands[3].Flags().String("format", graph.FormatDOT, "Graph format: dot, graphml or json")
+	artistSubcommands[3].Flags().String("file", "", "File to write the graph to instead of stdout")
+	artistCommands[0].AddCommand(artistSubcommands...)
+}
+
+func isAlbumGroup(group string) bool {
+	for _, name := range spotify.AlbumGroups {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var audioCommands = []*cobra.Command{
+	{
+		Use:     "features [track...]",
+		Short:   "Get the audio features of tracks, such as tempo, key, energy and danceability",
+		Example: "spotify-cli features \"Tighten Up\" 4uLU6hMCjMI75M1A2tKUQC\nspotify-cli features --album Brothers",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if album, _ := cmd.Flags().GetString("album"); album == "" && len(args) == 0 {
+				return errors.New("Must provide at least one track or --album")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+
+			var ids, names []string
+			if album, _ := cmd.Flags().GetString("album"); album != "" {
+				out, err := spotifyClient.GetAlbumTracks(cmd.Context(), album)
+				if err != nil {
+					fmt.Println("Failed to get album tracks:", err)
+					return
+				}
+				for _, track := range out.Tracks {
+					ids = append(ids, track.ID)
+					names = append(names, track.Name)
+				}
+			}
+			if len(args) > 0 {
+				var trackIDs []string
+				for _, arg := range args {
+					id, err := spotifyClient.ResolveID(cmd.Context(), arg, "track")
+					if err != nil {
+						fmt.Println("Failed to find track "+arg+":", err)
+						return
+					}
+					trackIDs = append(trackIDs, id)
+				}
+				tracks, err := spotifyClient.GetSeveralTracks(cmd.Context(), trackIDs)
+				if err != nil {
+					fmt.Println("Failed to get tracks:", err)
+					return
+				}
+				for i, track := range tracks.Tracks {
+					name := trackIDs[i]
+					if track != nil {
+						name = formatTrack(*track)
+					}
+					ids = append(ids, trackIDs[i])
+					names = append(names, name)
+				}
+			}
+
+			out, err := spotifyClient.GetAudioFeatures(cmd.Context(), ids)
+			if err != nil {
+				fmt.Println("Failed to get audio features:", err)
+				return
+			}
+
+			var rows [][]string
+			for i, features := range out.AudioFeatures {
+				if features == nil {
+					rows = append(rows, []string{names[i], "no features", "", "", "", "", "", "", "", "", "", ""})
+					continue
+				}
+				rows = append(rows, []string{
+					names[i],
+					formatFloat(features.Tempo, 1),
+					spotify.KeyName(features.Key, features.Mode),
+					fmt.Sprintf("%d/4", features.TimeSignature),
+					formatFloat(features.Energy, 2),
+					formatFloat(features.Valence, 2),
+					formatFloat(features.Danceability, 2),
+					formatFloat(features.Loudness, 1) + " dB",
+					formatFloat(features.Acousticness, 2),
+					formatFloat(features.Instrumentalness, 2),
+					formatFloat(features.Speechiness, 2),
+					formatFloat(features.Liveness, 2),
+				})
+			}
+			printOutput(cmd, []string{"Track", "Tempo", "Key", "Time", "Energy", "Valence", "Danceability", "Loudness", "Acousticness", "Instrumentalness", "Speechiness", "Liveness"}, rows, out.AudioFeatures)
+		},
+	},
+	{
+		Use:     "analysis <track>",
+		Short:   "Summarize the sections, bars and beats of a track",
+		Example: "spotify-cli analysis \"Tighten Up\"\nspotify-cli analysis 4uLU6hMCjMI75M1A2tKUQC --raw > analysis.json",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			analysis, err := spotifyClient.GetAudioAnalysis(cmd.Context(), args[0])
+			if err != nil {
+				fmt.Println("Failed to get audio analysis:", err)
+				return
+			}
+
+			if raw, _ := cmd.Flags().GetBool("raw"); raw {
+				os.Stdout.Write(analysis.Raw)
+				fmt.Println()
+				return
+			}
+			if format, _ := cmd.Flags().GetString("output"); format == outputText {
+				track := analysis.Track
+				fmt.Println("Duration:", formatSeconds(track.Duration))
+				fmt.Println(fmt.Sprintf("Tempo: %.1f BPM (confidence %.2f)", track.Tempo, track.TempoConfidence))
+				fmt.Println(fmt.Sprintf("Key: %s (confidence %.2f)", spotify.KeyName(track.Key, track.Mode), track.KeyConfidence))
+				fmt.Println(fmt.Sprintf("Time signature: %d/4 (confidence %.2f)", track.TimeSignature, track.TimeSignatureConfidence))
+				fmt.Println(fmt.Sprintf("Loudness: %.1f dB", track.Loudness))
+				fmt.Println(fmt.Sprintf("Fade in ends at %s, fade out starts at %s", formatSeconds(track.EndOfFadeIn), formatSeconds(track.StartOfFadeOut)))
+				fmt.Println(fmt.Sprintf("Sections: %d, bars: %d (%.2fs on average), beats: %d (%.2fs on average), tatums: %d, segments: %d",
+					len(analysis.Sections), len(analysis.Bars), averageDuration(analysis.Bars), len(analysis.Beats), averageDuration(analysis.Beats), len(analysis.Tatums), len(analysis.Segments)))
+				fmt.Println()
+			}
+
+			var rows [][]string
+			for i, section := range analysis.Sections {
+				rows = append(rows, []string{
+					strconv.Itoa(i + 1),
+					formatSeconds(section.Start),
+					formatSeconds(section.Duration),
+					formatFloat(section.Tempo, 1),
+					spotify.KeyName(section.Key, section.Mode),
+					fmt.Sprintf("%d/4", section.TimeSignature),
+					formatFloat(section.Loudness, 1) + " dB",
+				})
+			}
+			printOutput(cmd, []string{"Section", "Start", "Duration", "Tempo", "Key", "Time", "Loudness"}, rows, analysis)
+		},
+	},
+}
+
+func init() {
+	audioCommands[0].Flags().String("album", "", "Get the features of every track on an album")
+	audioCommands[1].Flags().Bool("raw", false, "Print the full analysis JSON as returned by Spotify")
+}
+
+func formatFloat(f float64, precision int) string {
+	return strconv.FormatFloat(f, 'f', precision, 64)
+}
+
+// formatSeconds formats seconds as minutes and seconds, like 3:07.5
+func formatSeconds(seconds float64) string {
+	minutes := int(seconds) / 60
+	return fmt.Sprintf("%d:%04.1f", minutes, seconds-float64(minutes*60))
+}
+
+func averageDuration(intervals []spotify.TimeInterval) float64 {
+	if len(intervals) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, interval := range intervals {
+		total += interval.Duration
+	}
+	return total / float64(len(intervals))
+}
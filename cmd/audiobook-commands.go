@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var audiobookCommand = &cobra.Command{
+	Use:   "audiobook <audiobook>",
+	Short: "Get an audiobook and a page of its chapters, with how far you got in each",
+	Long: "Audiobooks can be given by name, ID, URI or link. Chapters are listed in order, a page at a time with --limit and --offset. " +
+		"Audiobooks are only sold in some countries and are looked up in your account's country unless --market says otherwise.",
+	Example: "spotify-cli audiobook \"Dune\"\n" +
+		"spotify-cli audiobook spotify:audiobook:7iHfbu1YPACw6oZPAFJtqe --limit 50 --offset 50 --market GB",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+		market, _ := cmd.Flags().GetString("market")
+		if limit < 1 || limit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			return
+		}
+
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		audiobook, err := spotifyClient.GetAudiobook(cmd.Context(), strings.Join(args, " "), market)
+		if err != nil {
+			fmt.Println("Failed to get audiobook:", err)
+			return
+		}
+		chapters, err := spotifyClient.GetAudiobookChapters(cmd.Context(), audiobook.ID, market, limit, offset)
+		if err != nil {
+			fmt.Println("Failed to get chapters:", err)
+			return
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Println("Name:", audiobook.Name)
+			fmt.Println("Authors:", joinNames(audiobook.Authors))
+			if len(audiobook.Narrators) > 0 {
+				fmt.Println("Narrators:", joinNames(audiobook.Narrators))
+			}
+			fmt.Println("Publisher:", audiobook.Publisher)
+			if audiobook.Edition != "" {
+				fmt.Println("Edition:", audiobook.Edition)
+			}
+			fmt.Println("Chapters:", audiobook.TotalChapters)
+			if len(audiobook.Languages) > 0 {
+				fmt.Println("Languages:", strings.Join(audiobook.Languages, ", "))
+			}
+			if audiobook.Explicit {
+				fmt.Println("Explicit")
+			}
+			fmt.Println(audiobook.Description)
+			fmt.Println()
+		}
+
+		var rows [][]string
+		for _, chapter := range chapters.Items {
+			rows = append(rows, []string{strconv.Itoa(chapter.ChapterNumber), chapter.Name, formatDuration(chapter.DurationMS), formatResumePoint(chapter.ResumePoint, chapter.DurationMS), chapter.URI})
+		}
+		printOutput(cmd, []string{"#", "Name", "Duration", "Progress", "URI"}, rows, struct {
+			Audiobook *spotify.Audiobook                  `json:"audiobook"`
+			Chapters  *spotify.GetAudiobookChaptersOutput `json:"chapters"`
+		}{audiobook, chapters})
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText && chapters.Next != "" {
+			fmt.Printf("Showing %d to %d of %d, use --offset %d for more\n", offset+1, offset+len(chapters.Items), chapters.Total, offset+len(chapters.Items))
+		}
+	},
+}
+
+var audiobookSubcommands = []*cobra.Command{
+	{
+		Use:     "saved",
+		Short:   "List the audiobooks in your library",
+		Example: "spotify-cli audiobook saved",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			if err := printLibrary(cmd, spotifyClient, spotify.LibraryAudiobooks); err != nil {
+				fmt.Println("Failed to list saved audiobooks:", err)
+			}
+		},
+	},
+	{
+		Use:     "save <audiobook...>",
+		Short:   "Save audiobooks to your library",
+		Example: "spotify-cli audiobook save \"Dune\" spotify:audiobook:7iHfbu1YPACw6oZPAFJtqe",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			saveLibraryItems(cmd, spotify.LibraryAudiobooks, args)
+		},
+	},
+	{
+		Use:     "remove <audiobook...>",
+		Short:   "Remove audiobooks from your library",
+		Example: "spotify-cli audiobook remove spotify:audiobook:7iHfbu1YPACw6oZPAFJtqe",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			removeLibraryItems(cmd, spotify.LibraryAudiobooks, args)
+		},
+	},
+}
+
+var chapterCommand = &cobra.Command{
+	Use:     "chapter <chapter>",
+	Short:   "Get an audiobook chapter and where you left off in it",
+	Long:    "Chapters can't be searched for, so they have to be given by ID, URI or link, as listed by the audiobook command.",
+	Example: "spotify-cli chapter spotify:chapter:0D5wENdkdwbqlrHoaJ9g29",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		market, _ := cmd.Flags().GetString("market")
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		chapter, err := spotifyClient.GetChapter(cmd.Context(), args[0], market)
+		if err != nil {
+			fmt.Println("Failed to get chapter:", err)
+			return
+		}
+		audiobook := ""
+		if chapter.Audiobook != nil {
+			audiobook = chapter.Audiobook.Name
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Println("Name:", chapter.Name)
+			fmt.Println("Audiobook:", audiobook)
+			fmt.Println("Chapter:", chapter.ChapterNumber)
+			fmt.Println("Duration:", formatDuration(chapter.DurationMS))
+			if progress := formatResumePoint(chapter.ResumePoint, chapter.DurationMS); progress != "" {
+				fmt.Println("Progress:", progress)
+			}
+			fmt.Println(chapter.Description)
+			return
+		}
+		played, position := "", ""
+		if chapter.ResumePoint != nil {
+			played = strconv.FormatBool(chapter.ResumePoint.FullyPlayed)
+			position = formatDuration(chapter.ResumePoint.ResumePositionMS)
+		}
+		printOutput(cmd, []string{"Name", "Audiobook", "Chapter", "Duration", "Position", "Fully Played", "URI"},
+			[][]string{{chapter.Name, audiobook, strconv.Itoa(chapter.ChapterNumber), formatDuration(chapter.DurationMS), position, played, chapter.URI}}, chapter)
+	},
+}
+
+func init() {
+	audiobookCommand.Flags().Int("limit", 20, "Number of chapters to list, 1 to 50")
+	audiobookCommand.Flags().Int("offset", 0, "Number of chapters to skip")
+	audiobookCommand.AddCommand(audiobookSubcommands...)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var authCommands = []*cobra.Command{
+	{
+		Use:     "login",
+		Short:   "Log in to Spotify to use commands that act on your account",
+		Example: "spotify-cli login",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			user, err := spotify.Login(cmd.Context(), func(url string) {
+				fmt.Println("Open this URL in your browser to log in:")
+				fmt.Println(url)
+			})
+			if err != nil {
+				fmt.Println("Failed to log in:", err)
+				return
+			}
+			fmt.Println("Logged in as", user.DisplayName)
+		},
+	},
+	{
+		Use:     "logout",
+		Short:   "Forget the saved Spotify login",
+		Example: "spotify-cli logout",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := spotify.Logout(); err != nil {
+				fmt.Println("Failed to log out:", err)
+				return
+			}
+			fmt.Println("Logged out")
+		},
+	},
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cwseger/spotify-cli/backup"
+	cobra "github.com/spf13/cobra"
+)
+
+var backupCommands = []*cobra.Command{
+	{
+		Use:     "backup <path>",
+		Short:   "Back up your library, followed artists, playlists and profile to a directory or .tar.gz file",
+		Example: "spotify-cli backup spotify-backup.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			snapshot, err := backup.Create(cmd.Context(), spotifyClient, printProgress)
+			if err != nil {
+				fmt.Println("Failed to create backup:", err)
+				return
+			}
+			if err := backup.Write(snapshot, args[0]); err != nil {
+				fmt.Println("Failed to write backup:", err)
+				return
+			}
+			fmt.Println("Backed up", snapshot.Profile.DisplayName, "to", args[0])
+		},
+	},
+	{
+		Use:     "restore <path>",
+		Short:   "Restore a backup into the account you are logged in to",
+		Long:    "Restore a backup into the account you are logged in to, which can be a different one from the backup. Anything already in the account is left alone, and an interrupted restore picks up where it stopped when run again.",
+		Example: "spotify-cli restore spotify-backup.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshot, err := backup.Read(args[0])
+			if err != nil {
+				fmt.Println("Failed to read backup:", err)
+				return
+			}
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			statePath, _ := cmd.Flags().GetString("state")
+			if statePath == "" {
+				statePath = filepath.Clean(args[0]) + ".restore-state.json"
+			}
+			if err := backup.Restore(cmd.Context(), spotifyClient, snapshot, statePath, printProgress); err != nil {
+				fmt.Println("Failed to restore backup:", err)
+				fmt.Println("Run the command again to resume")
+				return
+			}
+			fmt.Println("Restored backup of", snapshot.Manifest.User.DisplayName, "from", snapshot.Manifest.CreatedAt.Format("2006-01-02"))
+		},
+	},
+}
+
+func init() {
+	backupCommands[1].Flags().String("state", "", "File to keep restore progress in (default <path>.restore-state.json)")
+}
+
+func printProgress(step string, done, total int) {
+	fmt.Println(fmt.Sprintf("%s: %d/%d", step, done, total))
+}
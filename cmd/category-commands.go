@@ -1,60 +1,268 @@
 package cmd
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
 
+	"github.com/cwseger/spotify-cli/fuzzy"
 	"github.com/cwseger/spotify-cli/spotify"
 	cobra "github.com/spf13/cobra"
 )
 
 var categoryCommands = []*cobra.Command{
 	{
-		Use:     "categories",
-		Short:   "Get a list of categories",
-		Example: "spotify-cli categories 2",
-		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) > 1 {
-				return errors.New("Too many args")
-			}
-			return nil
-		},
+		Use:   "categories",
+		Short: "Get a list of categories",
+		Long: "Lists the categories Spotify tags playlists with, by ID and name. The IDs, or the names, can be passed to category " +
+			"and category-playlist. Names are translated with --locale and the categories picked for a country with --country.",
+		Example: "spotify-cli categories --limit 10\nspotify-cli categories --all --country SE --locale sv_SE",
+		Args:    cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			input, all, err := browseInput(cmd)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
-				fmt.Println(err, "Failed to create new spotify client")
+				fmt.Println("Failed to create new spotify client:", err)
 				return
 			}
-			if len(args) < 1 {
-				args = []string{"50"}
+			var out *spotify.GetCategoriesOutput
+			if all {
+				out, err = spotifyClient.GetAllCategories(cmd.Context(), input)
+			} else {
+				out, err = spotifyClient.GetCategoryList(cmd.Context(), input)
 			}
-			out, err := spotifyClient.GetCategoryList(cmd.Context(), args[0])
 			if err != nil {
-				fmt.Println(err, "Failed to get category list")
+				fmt.Println("Failed to get category list:", err)
+				return
 			}
+			var rows [][]string
 			for _, category := range out.Inner.Items {
-				fmt.Println(category.Name)
+				rows = append(rows, []string{category.ID, category.Name})
 			}
+			printOutput(cmd, []string{"ID", "Name"}, rows, out.Inner.Items)
+			printMore(cmd, out.Inner.Paging, len(out.Inner.Items))
 		},
 	},
 	{
-		Use:     "category-playlist",
-		Short:   "Get a list of playlists tagged with the specified category",
-		Example: "spotify-cli category-playlist chill",
-		Args:    cobra.ExactArgs(1),
+		Use:   "category <category>",
+		Short: "Get a single category by ID or name",
+		Long: "Categories can be given by ID or by name, which doesn't have to be exact: " +
+			"the closest name in the category list for --country and --locale is used.",
+		Example: "spotify-cli category 0JQ5DAqbMKFzHmL4tf05da\nspotify-cli category mood\nspotify-cli category \"hip hop\" --locale de_DE",
+		Args:    cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			spotifyClient, err := spotify.NewClient()
+			input, _, err := browseInput(cmd)
 			if err != nil {
-				fmt.Println("Failed to create new spotify client")
+				fmt.Println(err)
 				return
 			}
-			out, err := spotifyClient.GetCategoryPlaylists(cmd.Context(), args[0])
+			spotifyClient, err := newClient(cmd)
 			if err != nil {
-				fmt.Println("Failed to get category")
+				fmt.Println("Failed to create new spotify client:", err)
+				return
 			}
-			for _, playlist := range out.Inner.Items {
-				fmt.Println(fmt.Sprintf("%s -- %s", playlist.Name, playlist.URI))
+			category, err := resolveCategory(cmd.Context(), spotifyClient, strings.Join(args, " "), input)
+			if err != nil {
+				fmt.Println(err)
+				return
 			}
+			icon := ""
+			if len(category.Icons) > 0 {
+				icon = category.Icons[0].URL
+			}
+			printOutput(cmd, []string{"ID", "Name", "Icon"}, [][]string{{category.ID, category.Name, icon}}, category)
 		},
 	},
+	{
+		Use:   "category-playlist <category>",
+		Short: "Get a list of playlists tagged with the specified category",
+		Long: "Categories can be given by ID or name, as with the category command. " +
+			"Lists a page of playlists at a time with --limit and --offset, or all of them with --all.",
+		Example: "spotify-cli category-playlist chill\nspotify-cli category-playlist 0JQ5DAqbMKFzHmL4tf05da --all --country GB",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			input, all, err := browseInput(cmd)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			category, err := resolveCategory(cmd.Context(), spotifyClient, strings.Join(args, " "), input)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			var out *spotify.GetCategoryPlaylistsOutput
+			if all {
+				out, err = spotifyClient.GetAllCategoryPlaylists(cmd.Context(), category.ID, input)
+			} else {
+				out, err = spotifyClient.GetCategoryPlaylists(cmd.Context(), category.ID, input)
+			}
+			if err != nil {
+				fmt.Println("Failed to get category playlists:", err)
+				return
+			}
+			printOutput(cmd, playlistHeaders, playlistRows(out.Inner.Items), out.Inner.Items)
+			printMore(cmd, out.Inner.Paging, len(out.Inner.Items))
+		},
+	},
+	{
+		Use:     "featured-playlists",
+		Short:   "Get the playlists Spotify features, with the message it shows above them",
+		Example: "spotify-cli featured-playlists\nspotify-cli featured-playlists --country SE --locale sv_SE --all",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			input, all, err := browseInput(cmd)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			var out *spotify.GetFeaturedPlaylistsOutput
+			if all {
+				out, err = spotifyClient.GetAllFeaturedPlaylists(cmd.Context(), input)
+			} else {
+				out, err = spotifyClient.GetFeaturedPlaylists(cmd.Context(), input)
+			}
+			if err != nil {
+				fmt.Println("Failed to get featured playlists:", err)
+				return
+			}
+			if format, _ := cmd.Flags().GetString("output"); format == outputText && out.Message != "" {
+				fmt.Println(out.Message)
+				fmt.Println()
+			}
+			printOutput(cmd, playlistHeaders, playlistRows(out.Inner.Items), out)
+			printMore(cmd, out.Inner.Paging, len(out.Inner.Items))
+		},
+	},
+}
+
+func init() {
+	for _, command := range categoryCommands {
+		command.Flags().String("country", "", "Country to browse, as an ISO 3166-1 alpha-2 code such as SE")
+		command.Flags().String("locale", "", "Language to get names and descriptions in, as a language and country code such as sv_SE")
+		if command.Name() == "category" {
+			continue
+		}
+		command.Flags().Int("limit", 20, "Number of items to get, 1 to 50")
+		command.Flags().Int("offset", 0, "Number of items to skip")
+		command.Flags().Bool("all", false, "Get every item from --offset on instead of a page")
+	}
+}
+
+// browseInput reads the flags every browse command has, and whether to get all pages
+func browseInput(cmd *cobra.Command) (*spotify.BrowseInput, bool, error) {
+	input := &spotify.BrowseInput{}
+	input.Country, _ = cmd.Flags().GetString("country")
+	input.Locale, _ = cmd.Flags().GetString("locale")
+	if cmd.Flags().Lookup("limit") == nil {
+		return input, false, nil
+	}
+	input.Limit, _ = cmd.Flags().GetInt("limit")
+	input.Offset, _ = cmd.Flags().GetInt("offset")
+	all, _ := cmd.Flags().GetBool("all")
+	if input.Limit < 1 || input.Limit > 50 {
+		return nil, false, fmt.Errorf("Limit must be between 1 and 50")
+	}
+	if input.Offset < 0 {
+		return nil, false, fmt.Errorf("Offset must not be negative")
+	}
+	return input, all, nil
+}
+
+// printMore tells text readers how to get the next page, if there is one
+func printMore(cmd *cobra.Command, page spotify.Paging, count int) {
+	if format, _ := cmd.Flags().GetString("output"); format != outputText || page.Next == "" {
+		return
+	}
+	fmt.Printf("Showing %d to %d of %d, use --offset %d or --all for more\n", page.Offset+1, page.Offset+count, page.Total, page.Offset+count)
+}
+
+var playlistHeaders = []string{"Name", "Owner", "Tracks", "URI"}
+
+func playlistRows(playlists []spotify.Playlist) [][]string {
+	var rows [][]string
+	for _, playlist := range playlists {
+		// Playlists that were taken down come back empty
+		if playlist.ID == "" {
+			continue
+		}
+		rows = append(rows, []string{playlist.Name, playlist.Owner.DisplayName, strconv.Itoa(playlist.Tracks.Total), playlist.URI})
+	}
+	return rows
+}
+
+// resolveCategory finds a category by ID, or by the name that's closest to
+// query among the categories for the country and locale
+func resolveCategory(ctx context.Context, spotifyClient spotify.Client, query string, input *spotify.BrowseInput) (*spotify.Category, error) {
+	out, err := spotifyClient.GetAllCategories(ctx, &spotify.BrowseInput{Country: input.Country, Locale: input.Locale})
+	if err != nil {
+		return nil, err
+	}
+	categories := out.Inner.Items
+	for i, category := range categories {
+		if category.ID == query {
+			return &categories[i], nil
+		}
+	}
+
+	want := normalizeName(query)
+	best, bestScore := -1, 0
+	for i, category := range categories {
+		name := normalizeName(category.Name)
+		score := 0
+		switch {
+		case name == want:
+			score = 1000
+		case strings.HasPrefix(name, want):
+			score = 500 - len(name)
+		case strings.Contains(name, want):
+			score = 400 - len(name)
+		default:
+			// Allow a typo or so for every four letters
+			if distance := fuzzy.EditDistance(name, want); distance <= len(want)/4 {
+				score = 300 - distance
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best >= 0 {
+		return &categories[best], nil
+	}
+
+	// Some categories work by ID without being listed
+	category, err := spotifyClient.GetCategory(ctx, query, input)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't find a category called %s", query)
+	}
+	return category, nil
+}
+
+// normalizeName lowercases a name and drops everything but letters and digits,
+// so "Hip-Hop" and "hip hop" compare equal
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var configCommand = &cobra.Command{
+	Use:   "config",
+	Short: "Get and set defaults for flags",
+	Long:  "The only setting so far is market, which is used by every command when --market isn't given.",
+}
+
+var configSubcommands = []*cobra.Command{
+	{
+		Use:     "get <key>",
+		Short:   "Print a setting",
+		Example: "spotify-cli config get market",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			config, err := spotify.LoadConfig()
+			if err != nil {
+				fmt.Println("Failed to load config:", err)
+				return
+			}
+			value, err := configValue(config, args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			fmt.Println(*value)
+		},
+	},
+	{
+		Use:     "set <key> <value>",
+		Short:   "Change a setting",
+		Example: "spotify-cli config set market SE\nspotify-cli config set market from_token",
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := configValue(&spotify.Config{}, args[0]); err != nil {
+				fmt.Println(err)
+				return
+			}
+			market, err := spotify.NormalizeMarket(args[1])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			updateConfig(args[0], market)
+		},
+	},
+	{
+		Use:     "unset <key>",
+		Short:   "Go back to the default for a setting",
+		Example: "spotify-cli config unset market",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			updateConfig(args[0], "")
+		},
+	},
+}
+
+// configValue points at the setting called key
+func configValue(config *spotify.Config, key string) (*string, error) {
+	switch key {
+	case "market":
+		return &config.Market, nil
+	}
+	return nil, fmt.Errorf("Unknown setting %q, must be market", key)
+}
+
+func updateConfig(key, value string) {
+	config, err := spotify.LoadConfig()
+	if err != nil {
+		fmt.Println("Failed to load config:", err)
+		return
+	}
+	setting, err := configValue(config, key)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	*setting = value
+	if err := config.Save(); err != nil {
+		fmt.Println("Failed to save config:", err)
+	}
+}
+
+func init() {
+	configCommand.AddCommand(configSubcommands...)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var followCommand = &cobra.Command{
+	Use:     "follow <artist|user|playlist> <item...>",
+	Short:   "Follow artists, users or playlists",
+	Example: "spotify-cli follow artist \"The Black Keys\"\nspotify-cli follow playlist spotify:playlist:37i9dQZF1DXcBWIGoYBM5M --private",
+	Args:    followArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		ids, err := resolveFollowTargets(cmd.Context(), spotifyClient, args[0], args[1:])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if args[0] == "playlist" {
+			private, _ := cmd.Flags().GetBool("private")
+			for _, id := range ids {
+				if err := spotifyClient.FollowPlaylist(cmd.Context(), id, !private); err != nil {
+					fmt.Println("Failed to follow playlist:", err)
+					return
+				}
+			}
+		} else if err := spotifyClient.Follow(cmd.Context(), args[0], ids); err != nil {
+			fmt.Println("Failed to follow:", err)
+			return
+		}
+		fmt.Println("Followed", len(ids), args[0]+"(s)")
+	},
+}
+
+var followCheckCommand = &cobra.Command{
+	Use:     "check <artist|user|playlist> <item...>",
+	Short:   "Check whether you follow artists, users or playlists",
+	Example: "spotify-cli follow check artist \"The Black Keys\" Khruangbin",
+	Args:    followArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		ids, err := resolveFollowTargets(cmd.Context(), spotifyClient, args[0], args[1:])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		var following []bool
+		if args[0] == "playlist" {
+			for _, id := range ids {
+				ok, err := spotifyClient.CheckFollowingPlaylist(cmd.Context(), id)
+				if err != nil {
+					fmt.Println("Failed to check followed playlist:", err)
+					return
+				}
+				following = append(following, ok)
+			}
+		} else {
+			following, err = spotifyClient.CheckFollowing(cmd.Context(), args[0], ids)
+			if err != nil {
+				fmt.Println("Failed to check following:", err)
+				return
+			}
+		}
+
+		type checkOutput struct {
+			Item      string `json:"item"`
+			ID        string `json:"id"`
+			Following bool   `json:"following"`
+		}
+		var raw []checkOutput
+		var rows [][]string
+		for i, id := range ids {
+			raw = append(raw, checkOutput{Item: args[i+1], ID: id, Following: following[i]})
+			rows = append(rows, []string{args[i+1], id, strconv.FormatBool(following[i])})
+		}
+		printOutput(cmd, []string{"Item", "ID", "Following"}, rows, raw)
+	},
+}
+
+var unfollowCommand = &cobra.Command{
+	Use:     "unfollow <artist|user|playlist> <item...>",
+	Short:   "Unfollow artists, users or playlists",
+	Example: "spotify-cli unfollow artist \"The Black Keys\"",
+	Args:    followArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		ids, err := resolveFollowTargets(cmd.Context(), spotifyClient, args[0], args[1:])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if args[0] == "playlist" {
+			for _, id := range ids {
+				if err := spotifyClient.UnfollowPlaylist(cmd.Context(), id); err != nil {
+					fmt.Println("Failed to unfollow playlist:", err)
+					return
+				}
+			}
+		} else if err := spotifyClient.Unfollow(cmd.Context(), args[0], ids); err != nil {
+			fmt.Println("Failed to unfollow:", err)
+			return
+		}
+		fmt.Println("Unfollowed", len(ids), args[0]+"(s)")
+	},
+}
+
+var followingCommand = &cobra.Command{
+	Use:     "following",
+	Short:   "List the artists you follow",
+	Example: "spotify-cli following\nspotify-cli following --limit 20 --after 0I2XqVXqHScXjHhk6AYYRe",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		after, _ := cmd.Flags().GetString("after")
+
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+
+		var out *spotify.GetFollowedArtistsOutput
+		if limit > 0 {
+			out, err = spotifyClient.GetFollowedArtistsPage(cmd.Context(), after, limit)
+		} else {
+			out, err = spotifyClient.GetFollowedArtists(cmd.Context())
+		}
+		if err != nil {
+			fmt.Println("Failed to get followed artists:", err)
+			return
+		}
+
+		var rows [][]string
+		for _, artist := range out.Inner.Items {
+			rows = append(rows, []string{artist.Name, strconv.Itoa(artist.Followers.Total), artist.URI})
+		}
+		printOutput(cmd, []string{"Name", "Followers", "URI"}, rows, out.Inner)
+		if format, _ := cmd.Flags().GetString("output"); format == outputText && out.Inner.Cursors.After != "" && out.Inner.Next != "" {
+			fmt.Println("Next page: --after", out.Inner.Cursors.After)
+		}
+	},
+}
+
+func init() {
+	followCommand.Flags().Bool("private", false, "Follow playlists privately")
+	followCommand.AddCommand(followCheckCommand)
+
+	followingCommand.Flags().Int("limit", 0, "Number of artists per page, 1 to 50 (default all artists)")
+	followingCommand.Flags().String("after", "", "Artist ID to start the page after, as printed at the end of the previous page")
+}
+
+func followArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("Must provide artist, user or playlist and at least one item")
+	}
+	switch args[0] {
+	case "artist", "user", "playlist":
+		return nil
+	}
+	return fmt.Errorf("Can only follow artist, user or playlist, not %q", args[0])
+}
+
+// resolveFollowTargets turns items into IDs. Artists and playlists can be
+// given by name, users only by ID, URI or link since they can't be searched.
+func resolveFollowTargets(ctx context.Context, spotifyClient spotify.Client, followType string, items []string) ([]string, error) {
+	var ids []string
+	for _, item := range items {
+		if followType == "user" {
+			id, ok := spotify.ParseID(item, "user")
+			if !ok {
+				id = item
+			}
+			ids = append(ids, id)
+			continue
+		}
+		id, err := spotifyClient.ResolveID(ctx, item, followType)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find %s: %v", item, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
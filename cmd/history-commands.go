@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/cwseger/spotify-cli/history"
+	"github.com/cwseger/spotify-cli/scrobble"
+	cobra "github.com/spf13/cobra"
+)
+
+var historyCommand = &cobra.Command{
+	Use:   "history",
+	Short: "Keep a long-term log of everything you listen to",
+	Long:  "Spotify only remembers your last 50 plays. The history commands copy them into a local log so nothing is lost, as long as they run often enough.",
+}
+
+var historyCommands = []*cobra.Command{
+	{
+		Use:     "sync",
+		Short:   "Add your recently played tracks to the local history",
+		Example: "spotify-cli history sync",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			store, err := openHistory(cmd)
+			if err != nil {
+				fmt.Println("Failed to open history:", err)
+				return
+			}
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			added, err := history.Sync(cmd.Context(), spotifyClient, store)
+			if err != nil {
+				fmt.Println("Failed to sync history:", err)
+				return
+			}
+			fmt.Println(fmt.Sprintf("Added %d plays, %d in total", added, store.Count()))
+		},
+	},
+	{
+		Use:     "daemon",
+		Short:   "Keep syncing your recently played tracks to the local history on an interval",
+		Example: "spotify-cli history daemon --interval 1h",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				fmt.Println("Interval must be positive")
+				return
+			}
+			store, err := openHistory(cmd)
+			if err != nil {
+				fmt.Println("Failed to open history:", err)
+				return
+			}
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt)
+			go func() {
+				<-signals
+				cancel()
+			}()
+
+			fmt.Println("Syncing history every", interval)
+			history.Daemon(ctx, spotifyClient, store, interval, func(added int, err error) {
+				now := time.Now().Format("2006-01-02 15:04:05")
+				if err != nil {
+					if ctx.Err() == nil {
+						fmt.Println(now, "Failed to sync history:", err)
+					}
+					return
+				}
+				fmt.Println(fmt.Sprintf("%s Added %d plays, %d in total", now, added, store.Count()))
+			})
+		},
+	},
+	{
+		Use:     "export [file]",
+		Short:   "Export the history as ListenBrainz listens or Last.fm scrobbles",
+		Long:    "Writes the history as a ListenBrainz import submission in JSON, or as Last.fm scrobble CSV with Artist, Track, Album, Timestamp, Album Artist and Duration columns. Prints to stdout when no file is given.",
+		Example: "spotify-cli history export listens.json\nspotify-cli history export scrobbles.csv --format lastfm --from 2024-01-01",
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			write, ok := exportFormats[format]
+			if !ok {
+				fmt.Println("Format must be listenbrainz or lastfm")
+				return
+			}
+			from, to, err := dateRange(cmd)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			store, err := openHistory(cmd)
+			if err != nil {
+				fmt.Println("Failed to open history:", err)
+				return
+			}
+			var plays []history.Play
+			if err := store.Read(from, to, func(play history.Play) error {
+				plays = append(plays, play)
+				return nil
+			}); err != nil {
+				fmt.Println("Failed to read history:", err)
+				return
+			}
+
+			if len(args) == 0 {
+				if err := write(os.Stdout, plays); err != nil {
+					fmt.Println("Failed to export history:", err)
+				}
+				return
+			}
+			file, err := os.Create(args[0])
+			if err != nil {
+				fmt.Println("Failed to create export:", err)
+				return
+			}
+			if err := write(file, plays); err != nil {
+				file.Close()
+				fmt.Println("Failed to export history:", err)
+				return
+			}
+			if err := file.Close(); err != nil {
+				fmt.Println("Failed to export history:", err)
+				return
+			}
+			fmt.Println("Exported", len(plays), "plays to", args[0])
+		},
+	},
+	{
+		Use:   "submit",
+		Short: "Submit the plays not yet sent to ListenBrainz or a compatible service",
+		Long: "Submits every play newer than the last one sent to the endpoint. What was sent is remembered per endpoint in the history directory, so it is safe to run on a schedule. " +
+			"The user token comes from --token or LISTENBRAINZ_TOKEN and the endpoint from --endpoint or LISTENBRAINZ_URL.",
+		Example: "spotify-cli history submit\nspotify-cli history submit --endpoint http://localhost:8100 --since 2024-01-01",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, _ := cmd.Flags().GetString("endpoint")
+			if endpoint == "" {
+				endpoint = os.Getenv("LISTENBRAINZ_URL")
+			}
+			if endpoint == "" {
+				endpoint = scrobble.DefaultEndpoint
+			}
+			token, _ := cmd.Flags().GetString("token")
+			if token == "" {
+				token = os.Getenv("LISTENBRAINZ_TOKEN")
+			}
+			if token == "" {
+				fmt.Println("Must provide a user token with --token or LISTENBRAINZ_TOKEN")
+				return
+			}
+			var since time.Time
+			if value, _ := cmd.Flags().GetString("since"); value != "" {
+				day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+				if err != nil {
+					fmt.Println("Since must be a date like 2024-01-31")
+					return
+				}
+				since = day
+			}
+
+			dir, err := historyDir(cmd)
+			if err != nil {
+				fmt.Println("Failed to open history:", err)
+				return
+			}
+			store, err := history.Open(dir)
+			if err != nil {
+				fmt.Println("Failed to open history:", err)
+				return
+			}
+			submitter := scrobble.NewSubmitter(endpoint, token)
+			sent, err := scrobble.Submit(cmd.Context(), store, submitter, filepath.Join(dir, "submitted.json"), since, func(done, total int) {
+				fmt.Println(fmt.Sprintf("Submitted %d/%d", done, total))
+			})
+			if err != nil {
+				fmt.Println("Failed to submit history:", err)
+				return
+			}
+			fmt.Println("Submitted", sent, "plays to", submitter.Endpoint())
+		},
+	},
+}
+
+var exportFormats = map[string]func(io.Writer, []history.Play) error{
+	"listenbrainz": scrobble.WriteListenBrainz,
+	"lastfm":       scrobble.WriteLastFM,
+}
+
+func init() {
+	historyCommand.PersistentFlags().String("dir", "", "Directory the history is kept in (default history in the user config directory)")
+	historyCommands[1].Flags().Duration("interval", 30*time.Minute, "How often to sync")
+	historyCommands[2].Flags().String("format", "listenbrainz", "Export format: listenbrainz or lastfm")
+	historyCommands[2].Flags().String("from", "", "First day to export, as YYYY-MM-DD (default the start of the history)")
+	historyCommands[2].Flags().String("to", "", "Last day to export, as YYYY-MM-DD (default today)")
+	historyCommands[3].Flags().String("endpoint", "", "ListenBrainz compatible API to submit to (default "+scrobble.DefaultEndpoint+")")
+	historyCommands[3].Flags().String("token", "", "User token for the API")
+	historyCommands[3].Flags().String("since", "", "Don't submit plays before this day, as YYYY-MM-DD")
+	historyCommand.AddCommand(historyCommands...)
+}
+
+func openHistory(cmd *cobra.Command) (*history.Store, error) {
+	dir, err := historyDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(dir)
+}
+
+func historyDir(cmd *cobra.Command) (string, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		return history.DefaultDir()
+	}
+	return dir, nil
+}
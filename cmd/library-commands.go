@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var libraryTypeNames = "tracks, albums, shows, episodes or audiobooks"
+
+var libraryCommand = &cobra.Command{
+	Use:   "library",
+	Short: "Manage the tracks, albums, shows, episodes and audiobooks saved in your library",
+}
+
+var libraryCommands = []*cobra.Command{
+	{
+		Use:     "list <type>",
+		Short:   "List the " + libraryTypeNames + " in your library",
+		Example: "spotify-cli library list albums --output csv",
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			libraryType, err := spotify.ParseLibraryType(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			if err := printLibrary(cmd, spotifyClient, libraryType); err != nil {
+				fmt.Println("Failed to list saved "+string(libraryType)+":", err)
+			}
+		},
+	},
+	{
+		Use:     "save <type> <item...>",
+		Short:   "Save " + libraryTypeNames + " to your library",
+		Example: "spotify-cli library save albums spotify:album:4m2880jivSbbyEGAKfITCa \"Brothers\"",
+		Args:    libraryItemArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			libraryType, err := spotify.ParseLibraryType(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			saveLibraryItems(cmd, libraryType, args[1:])
+		},
+	},
+	{
+		Use:     "remove <type> <item...>",
+		Short:   "Remove " + libraryTypeNames + " from your library",
+		Example: "spotify-cli library remove tracks spotify:track:6rqhFgbbKwnb9MLmUQDhG6",
+		Args:    libraryItemArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			libraryType, err := spotify.ParseLibraryType(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			removeLibraryItems(cmd, libraryType, args[1:])
+		},
+	},
+	{
+		Use:     "contains <type> <item...>",
+		Short:   "Check whether " + libraryTypeNames + " are in your library",
+		Example: "spotify-cli library contains tracks spotify:track:6rqhFgbbKwnb9MLmUQDhG6",
+		Args:    libraryItemArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			libraryType, err := spotify.ParseLibraryType(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			ids, spotifyClient, err := resolveLibraryItems(cmd, libraryType, args[1:])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			saved, err := spotifyClient.CheckLibrary(cmd.Context(), libraryType, ids)
+			if err != nil {
+				fmt.Println("Failed to check library:", err)
+				return
+			}
+
+			type containsOutput struct {
+				Item  string `json:"item"`
+				ID    string `json:"id"`
+				Saved bool   `json:"saved"`
+			}
+			var raw []containsOutput
+			var rows [][]string
+			for i, id := range ids {
+				raw = append(raw, containsOutput{Item: args[i+1], ID: id, Saved: saved[i]})
+				rows = append(rows, []string{args[i+1], id, strconv.FormatBool(saved[i])})
+			}
+			printOutput(cmd, []string{"Item", "ID", "Saved"}, rows, raw)
+		},
+	},
+}
+
+func init() {
+	libraryCommand.AddCommand(libraryCommands...)
+}
+
+func libraryItemArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("Must provide a library type and at least one item")
+	}
+	return nil
+}
+
+// resolveLibraryItems turns the items given on the command line into IDs.
+// Items can be IDs, URIs, links or names to search for.
+func resolveLibraryItems(cmd *cobra.Command, libraryType spotify.LibraryType, items []string) ([]string, *spotify.DefaultClient, error) {
+	spotifyClient, err := newUserClient(cmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create new spotify client: %v", err)
+	}
+
+	var ids []string
+	for _, item := range items {
+		id, err := spotifyClient.ResolveID(cmd.Context(), item, libraryType.ResourceType())
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to find %s: %v", item, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, spotifyClient, nil
+}
+
+func saveLibraryItems(cmd *cobra.Command, libraryType spotify.LibraryType, items []string) {
+	ids, spotifyClient, err := resolveLibraryItems(cmd, libraryType, items)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := spotifyClient.SaveToLibrary(cmd.Context(), libraryType, ids); err != nil {
+		fmt.Println("Failed to save to library:", err)
+		return
+	}
+	fmt.Println("Saved", len(ids), libraryType)
+}
+
+func removeLibraryItems(cmd *cobra.Command, libraryType spotify.LibraryType, items []string) {
+	ids, spotifyClient, err := resolveLibraryItems(cmd, libraryType, items)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := spotifyClient.RemoveFromLibrary(cmd.Context(), libraryType, ids); err != nil {
+		fmt.Println("Failed to remove from library:", err)
+		return
+	}
+	fmt.Println("Removed", len(ids), libraryType)
+}
+
+func printLibrary(cmd *cobra.Command, spotifyClient spotify.Client, libraryType spotify.LibraryType) error {
+	ctx := cmd.Context()
+	switch libraryType {
+	case spotify.LibraryTracks:
+		out, err := spotifyClient.GetSavedTracks(ctx)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, item := range out.Items {
+			rows = append(rows, []string{item.Track.Name, artistNames(item.Track.Artists), item.Track.Album.Name, item.AddedAt, item.Track.URI})
+		}
+		printOutput(cmd, []string{"Name", "Artist", "Album", "Added", "URI"}, rows, out.Items)
+	case spotify.LibraryAlbums:
+		out, err := spotifyClient.GetSavedAlbums(ctx)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, item := range out.Items {
+			rows = append(rows, []string{item.Album.Name, artistNames(item.Album.Artists), item.Album.ReleaseDate, item.AddedAt, item.Album.URI})
+		}
+		printOutput(cmd, []string{"Name", "Artist", "Release Date", "Added", "URI"}, rows, out.Items)
+	case spotify.LibraryShows:
+		out, err := spotifyClient.GetSavedShows(ctx)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, item := range out.Items {
+			rows = append(rows, []string{item.Show.Name, item.Show.Publisher, strconv.Itoa(item.Show.TotalEpisodes), item.AddedAt, item.Show.URI})
+		}
+		printOutput(cmd, []string{"Name", "Publisher", "Episodes", "Added", "URI"}, rows, out.Items)
+	case spotify.LibraryEpisodes:
+		out, err := spotifyClient.GetSavedEpisodes(ctx)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, item := range out.Items {
+			show := ""
+			if item.Episode.Show != nil {
+				show = item.Episode.Show.Name
+			}
+			rows = append(rows, []string{item.Episode.Name, show, item.Episode.ReleaseDate, item.AddedAt, item.Episode.URI})
+		}
+		printOutput(cmd, []string{"Name", "Show", "Release Date", "Added", "URI"}, rows, out.Items)
+	case spotify.LibraryAudiobooks:
+		out, err := spotifyClient.GetSavedAudiobooks(ctx)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, item := range out.Items {
+			rows = append(rows, []string{item.Name, joinNames(item.Authors), strconv.Itoa(item.TotalChapters), item.URI})
+		}
+		printOutput(cmd, []string{"Name", "Author", "Chapters", "URI"}, rows, out.Items)
+	}
+	return nil
+}
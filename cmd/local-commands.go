@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/local"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var localCommand = &cobra.Command{
+	Use:   "local",
+	Short: "Work with music files on your computer",
+}
+
+var localSubcommands = []*cobra.Command{
+	{
+		Use:   "match <dir>",
+		Short: "Find the Spotify tracks for the music files in a directory",
+		Long: "Reads the tags of the MP3, FLAC, Ogg Vorbis, Opus and MP4 files under a directory and finds each on Spotify, " +
+			"by ISRC when the file has one and otherwise by searching for its title and artist and scoring the results on " +
+			"title, artist, album and duration. Files without tags are looked up by their names, as in \"Artist - Title.mp3\".\n\n" +
+			"Use -o csv for a mapping of files to tracks, and --playlist to put the matched tracks in a playlist, " +
+			"which is created if you don't have one by that name and otherwise replaced. " +
+			"Matches below --min-confidence are listed but left out of the playlist.",
+		Example: "spotify-cli local match ~/Music -o csv > matches.csv\n" +
+			"spotify-cli local match ~/Music/Jazz --playlist \"Jazz from disk\" --min-confidence 0.8",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+			playlist, _ := cmd.Flags().GetString("playlist")
+			if minConfidence < 0 || minConfidence > 1 {
+				fmt.Println("Minimum confidence must be between 0 and 1")
+				return
+			}
+
+			paths, err := local.Scan(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			if len(paths) == 0 {
+				fmt.Println("No music files found in", args[0])
+				return
+			}
+
+			// Only playlists need the user's token
+			var spotifyClient *spotify.DefaultClient
+			if playlist != "" {
+				spotifyClient, err = newUserClient(cmd)
+			} else {
+				spotifyClient, err = newClient(cmd)
+			}
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			matches, err := local.MatchFiles(cmd.Context(), spotifyClient, paths, local.Options{Concurrency: concurrency})
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			var rows [][]string
+			var uris []string
+			seen := map[string]bool{}
+			for _, match := range matches {
+				status := matchStatus(match, minConfidence)
+				if status == "matched" && !seen[match.Track.URI] {
+					seen[match.Track.URI] = true
+					uris = append(uris, match.Track.URI)
+				}
+				rows = append(rows, matchRow(args[0], match, status))
+			}
+			printOutput(cmd, []string{"File", "Artist", "Title", "Album", "ISRC", "Duration", "Match", "URI", "Confidence", "Method", "Status"}, rows, matches)
+
+			if playlist == "" {
+				return
+			}
+			created, err := buildPlaylist(cmd.Context(), spotifyClient, playlist, uris)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			if format, _ := cmd.Flags().GetString("output"); format == outputText {
+				verb := "Replaced the tracks of"
+				if created {
+					verb = "Created"
+				}
+				fmt.Printf("%s %s with %d of %d files\n", verb, playlist, len(uris), len(matches))
+			}
+		},
+	},
+}
+
+// matchStatus sums up a match as matched, low confidence, not found or unreadable
+func matchStatus(match local.Match, minConfidence float64) string {
+	switch {
+	case match.Error != "":
+		return "unreadable"
+	case match.Track == nil:
+		return "not found"
+	case match.Confidence < minConfidence:
+		return "low confidence"
+	}
+	return "matched"
+}
+
+func matchRow(dir string, match local.Match, status string) []string {
+	file := match.Path
+	if rel, err := filepath.Rel(dir, match.Path); err == nil {
+		file = rel
+	}
+	row := []string{file, "", "", "", "", "", "", "", "", match.Method, status}
+	if match.Tags != nil {
+		row[1], row[2], row[3], row[4] = match.Tags.Artist, match.Tags.Title, match.Tags.Album, match.Tags.ISRC
+		if match.Tags.Duration > 0 {
+			row[5] = formatDuration(int(match.Tags.Duration.Milliseconds()))
+		}
+	}
+	if match.Track != nil {
+		row[6], row[7] = formatTrack(*match.Track), match.Track.URI
+		row[8] = fmt.Sprintf("%.2f", match.Confidence)
+	}
+	return row
+}
+
+// buildPlaylist sets the tracks of the user's playlist with that name, or ID,
+// creating it if there isn't one. It reports whether it was created.
+func buildPlaylist(ctx context.Context, spotifyClient spotify.Client, playlist string, uris []string) (bool, error) {
+	playlistID, ok := spotify.ParseID(playlist, "playlist")
+	if !ok {
+		playlists, err := spotifyClient.GetCurrentUserPlaylists(ctx)
+		if err != nil {
+			return false, fmt.Errorf("Failed to get playlists: %v", err)
+		}
+		for _, p := range playlists.Items {
+			if strings.EqualFold(p.Name, playlist) {
+				playlistID = p.ID
+				break
+			}
+		}
+	}
+
+	created := false
+	if playlistID == "" {
+		user, err := spotifyClient.GetCurrentUser(ctx)
+		if err != nil {
+			return false, fmt.Errorf("Failed to get current user: %v", err)
+		}
+		out, err := spotifyClient.CreatePlaylist(ctx, user.ID, &spotify.CreatePlaylistInput{
+			Name:        playlist,
+			Description: "Matched from local files by spotify-cli",
+		})
+		if err != nil {
+			return false, fmt.Errorf("Failed to create playlist: %v", err)
+		}
+		playlistID, created = out.ID, true
+	}
+	if err := spotifyClient.ReplacePlaylistTracks(ctx, playlistID, uris); err != nil {
+		return false, fmt.Errorf("Failed to add tracks to playlist: %v", err)
+	}
+	return created, nil
+}
+
+func init() {
+	localSubcommands[0].Flags().Int("concurrency", 4, "How many files to match at once")
+	localSubcommands[0].Flags().Float64("min-confidence", 0.7, "Lowest confidence, from 0 to 1, to count as a match")
+	localSubcommands[0].Flags().String("playlist", "", "Name or ID of a playlist to put the matched tracks in")
+	localCommand.AddCommand(localSubcommands...)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var lookupCommand = &cobra.Command{
+	Use:   "lookup",
+	Short: "Find tracks by ISRC and albums by UPC",
+	Long: "Looks up recordings and releases by their industry codes. Every match is listed, so a code can turn up the " +
+		"original release along with remasters, reissues and compilations.",
+}
+
+var lookupSubcommands = []*cobra.Command{
+	{
+		Use:     "isrc <code...>",
+		Short:   "Find every track with an ISRC",
+		Example: "spotify-cli lookup isrc USUM71703861\nspotify-cli lookup isrc GBAYE0601498 US-UM7-17-03861 -o csv",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			codes, err := normalizeCodes(args, spotify.NormalizeISRC)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+
+			type match struct {
+				ISRC   string          `json:"isrc"`
+				Tracks []spotify.Track `json:"tracks"`
+			}
+			var matches []match
+			var rows [][]string
+			var missing []string
+			for _, code := range codes {
+				tracks, err := spotifyClient.SearchTracksByISRC(cmd.Context(), code)
+				if err != nil {
+					fmt.Printf("Failed to look up %s: %v\n", code, err)
+					return
+				}
+				matches = append(matches, match{code, tracks})
+				if len(tracks) == 0 {
+					missing = append(missing, code)
+				}
+				for _, track := range tracks {
+					rows = append(rows, []string{code, track.Name, artistNames(track.Artists), track.Album.Name, track.Album.AlbumType, track.Album.ReleaseDate, track.URI})
+				}
+			}
+			printOutput(cmd, []string{"ISRC", "Name", "Artists", "Album", "Album Type", "Released", "URI"}, rows, matches)
+			printMissing(cmd, "tracks", missing)
+		},
+	},
+	{
+		Use:     "upc <code...>",
+		Short:   "Find every album with a UPC or EAN",
+		Example: "spotify-cli lookup upc 00602557382594\nspotify-cli lookup upc 886443927087 5099902895529 -o json",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			codes, err := normalizeCodes(args, spotify.NormalizeUPC)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			spotifyClient, err := newClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+
+			type match struct {
+				UPC    string          `json:"upc"`
+				Albums []spotify.Album `json:"albums"`
+			}
+			var matches []match
+			var rows [][]string
+			var missing []string
+			for _, code := range codes {
+				albums, err := spotifyClient.SearchAlbumsByUPC(cmd.Context(), code)
+				if err != nil {
+					fmt.Printf("Failed to look up %s: %v\n", code, err)
+					return
+				}
+				matches = append(matches, match{code, albums})
+				if len(albums) == 0 {
+					missing = append(missing, code)
+				}
+				for _, album := range albums {
+					rows = append(rows, []string{code, album.Name, artistNames(album.Artists), album.AlbumType, album.ReleaseDate, strconv.Itoa(album.TotalTracks), album.Label, album.URI})
+				}
+			}
+			printOutput(cmd, []string{"UPC", "Name", "Artists", "Type", "Released", "Tracks", "Label", "URI"}, rows, matches)
+			printMissing(cmd, "albums", missing)
+		},
+	},
+}
+
+// normalizeCodes checks every code before any are looked up
+func normalizeCodes(args []string, normalize func(string) (string, error)) ([]string, error) {
+	var codes []string
+	for _, arg := range args {
+		code, err := normalize(arg)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// printMissing lists the codes nothing was found for in text output. In CSV
+// they just have no rows, and in JSON an empty list.
+func printMissing(cmd *cobra.Command, items string, missing []string) {
+	if format, _ := cmd.Flags().GetString("output"); format != outputText || len(missing) == 0 {
+		return
+	}
+	fmt.Printf("No %s found for %s\n", items, strings.Join(missing, ", "))
+}
+
+func init() {
+	lookupCommand.AddCommand(lookupSubcommands...)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var availabilityCommand = &cobra.Command{
+	Use:   "availability <track|album>",
+	Short: "List the markets a track or album is available in",
+	Long: "Tracks and albums can be given by name, ID, URI or link, and are taken to be albums with --album or when the URI or link is an album's. " +
+		"With --market, also shows whether the item is playable there, why not if it isn't, and the track it was relinked from if Spotify " +
+		"swapped in another version.",
+	Example: "spotify-cli availability \"Bohemian Rhapsody\"\n" +
+		"spotify-cli availability spotify:album:4aawyAB9vmqN3uQ7FjRGTy --market JP\n" +
+		"spotify-cli availability --album \"Control\" --market from_token",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		item := strings.Join(args, " ")
+		album, _ := cmd.Flags().GetBool("album")
+		album = album || isAlbumLink(item)
+		market, _ := cmd.Flags().GetString("market")
+
+		// Spotify leaves out available_markets when a market is given, so the
+		// catalog client mustn't have one
+		catalogClient, err := spotify.NewClient()
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		availability, err := getAvailability(cmd, catalogClient, item, album)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if market != "" {
+			var marketClient *spotify.DefaultClient
+			if market == spotify.FromToken {
+				marketClient, err = newUserClient(cmd)
+			} else {
+				marketClient, err = newClient(cmd)
+			}
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			inMarket, err := getAvailability(cmd, marketClient, availability.URI, album)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			availability.Market = market
+			availability.IsPlayable = inMarket.IsPlayable
+			availability.Restrictions = inMarket.Restrictions
+			availability.LinkedFrom = inMarket.LinkedFrom
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Println("Name:", availability.Name)
+			fmt.Println("Artists:", artistNames(availability.Artists))
+			if len(availability.AvailableMarkets) == 0 {
+				fmt.Println("Not available in any market")
+			} else {
+				fmt.Printf("Available in %d markets: %s\n", len(availability.AvailableMarkets), strings.Join(availability.AvailableMarkets, ", "))
+			}
+			if market != "" {
+				fmt.Printf("Playable in %s: %s\n", market, formatPlayable(availability))
+			}
+			if availability.LinkedFrom != nil {
+				fmt.Println("Relinked from:", availability.LinkedFrom.URI)
+			}
+			return
+		}
+		playable, reason, linkedFrom := "", "", ""
+		if availability.IsPlayable != nil {
+			playable = strconv.FormatBool(*availability.IsPlayable)
+		}
+		if availability.Restrictions != nil {
+			reason = availability.Restrictions.Reason
+		}
+		if availability.LinkedFrom != nil {
+			linkedFrom = availability.LinkedFrom.URI
+		}
+		printOutput(cmd, []string{"Name", "URI", "Markets", "Market", "Playable", "Restriction", "Linked From"},
+			[][]string{{availability.Name, availability.URI, strings.Join(availability.AvailableMarkets, " "), availability.Market, playable, reason, linkedFrom}}, availability)
+	},
+}
+
+// availability is what the availability command reports, for tracks and albums alike
+type availability struct {
+	Name             string                `json:"name"`
+	URI              string                `json:"uri"`
+	Artists          []spotify.Artist      `json:"artists"`
+	AvailableMarkets []string              `json:"available_markets"`
+	Market           string                `json:"market,omitempty"`
+	IsPlayable       *bool                 `json:"is_playable,omitempty"`
+	Restrictions     *spotify.Restrictions `json:"restrictions,omitempty"`
+	LinkedFrom       *spotify.LinkedTrack  `json:"linked_from,omitempty"`
+}
+
+func getAvailability(cmd *cobra.Command, spotifyClient spotify.Client, item string, album bool) (*availability, error) {
+	if album {
+		out, err := spotifyClient.GetAlbum(cmd.Context(), item)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get album: %v", err)
+		}
+		return &availability{
+			Name:             out.Name,
+			URI:              out.URI,
+			Artists:          out.Artists,
+			AvailableMarkets: out.AvailableMarkets,
+			IsPlayable:       out.IsPlayable,
+			Restrictions:     out.Restrictions,
+		}, nil
+	}
+	track, err := spotifyClient.GetTrack(cmd.Context(), item)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get track: %v", err)
+	}
+	return &availability{
+		Name:             track.Name,
+		URI:              track.URI,
+		Artists:          track.Artists,
+		AvailableMarkets: track.AvailableMarkets,
+		IsPlayable:       track.IsPlayable,
+		Restrictions:     track.Restrictions,
+		LinkedFrom:       track.LinkedFrom,
+	}, nil
+}
+
+func isAlbumLink(item string) bool {
+	return strings.HasPrefix(item, "spotify:album:") || strings.Contains(item, "open.spotify.com/album/")
+}
+
+func formatPlayable(a *availability) string {
+	switch {
+	case a.IsPlayable == nil:
+		return "unknown"
+	case *a.IsPlayable:
+		return "yes"
+	case a.Restrictions != nil && a.Restrictions.Reason != "":
+		return "no, restricted by " + a.Restrictions.Reason
+	default:
+		return "no"
+	}
+}
+
+// newClient creates a client credentials client that uses the --market flag
+func newClient(cmd *cobra.Command) (*spotify.DefaultClient, error) {
+	spotifyClient, err := spotify.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	market, _ := cmd.Flags().GetString("market")
+	spotifyClient.SetMarket(market)
+	return spotifyClient, nil
+}
+
+// newUserClient creates a client with the saved user token that uses the --market flag
+func newUserClient(cmd *cobra.Command) (*spotify.DefaultClient, error) {
+	spotifyClient, err := spotify.NewUserClient()
+	if err != nil {
+		return nil, err
+	}
+	market, _ := cmd.Flags().GetString("market")
+	spotifyClient.SetMarket(market)
+	return spotifyClient, nil
+}
+
+// resolveMarket checks --market, falling back to the market set with the
+// config command when it isn't given
+func resolveMarket(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("market")
+	if flag == nil {
+		return nil
+	}
+	market := flag.Value.String()
+	if !flag.Changed {
+		config, err := spotify.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("Failed to load config: %v", err)
+		}
+		market = config.Market
+	}
+	if market == "" {
+		return nil
+	}
+	market, err := spotify.NormalizeMarket(market)
+	if err != nil {
+		return err
+	}
+	return cmd.Flags().Set("market", market)
+}
+
+func init() {
+	availabilityCommand.Flags().Bool("album", false, "Look up an album instead of a track")
+}
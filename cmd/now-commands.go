@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	cobra "github.com/spf13/cobra"
+)
+
+var nowCommand = &cobra.Command{
+	Use:     "now",
+	Short:   "Show the track you're listening to",
+	Example: "spotify-cli now --art",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		out, err := spotifyClient.GetCurrentlyPlaying(cmd.Context())
+		if err == spotify.ErrMissingPermission {
+			fmt.Println(err)
+			return
+		}
+		if err != nil {
+			fmt.Println("Failed to get currently playing track:", err)
+			return
+		}
+		if out.Item == nil {
+			switch out.CurrentlyPlayingType {
+			case "episode":
+				fmt.Println("Playing a podcast episode")
+			case "ad":
+				fmt.Println("Playing an ad")
+			default:
+				fmt.Println("Nothing is playing")
+			}
+			return
+		}
+
+		track := out.Item
+		state := "Playing"
+		if !out.IsPlaying {
+			state = "Paused"
+		}
+		showArt(cmd, track.Album.Images)
+		printOutput(cmd, []string{"State", "Track", "Album", "Progress", "URI"}, [][]string{{
+			state,
+			formatTrack(*track),
+			track.Album.Name,
+			formatDuration(out.ProgressMS) + " / " + formatDuration(track.DurationMS),
+			track.URI,
+		}}, out)
+	},
+}
+
+func init() {
+	addArtFlags(nowCommand)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+// Output formats accepted by --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputCSV  = "csv"
+)
+
+// printOutput prints rows under headers in the format picked with --output.
+// JSON output prints raw instead, so it carries every field of the response.
+func printOutput(cmd *cobra.Command, headers []string, rows [][]string, raw interface{}) {
+	format, _ := cmd.Flags().GetString("output")
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(raw); err != nil {
+			fmt.Println("Failed to encode output:", err)
+		}
+	case outputCSV:
+		writer := csv.NewWriter(os.Stdout)
+		writer.Write(headers)
+		writer.WriteAll(rows)
+	default:
+		fmt.Println(strings.Join(headers, " | "))
+		for _, row := range rows {
+			fmt.Println(strings.Join(row, " | "))
+		}
+	}
+}
+
+func validateOutputFormat(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("output")
+	switch format {
+	case outputText, outputJSON, outputCSV:
+		return nil
+	}
+	return fmt.Errorf("Unknown output format %q, must be one of text, json or csv", format)
+}
+
+func formatTrack(track spotify.Track) string {
+	return fmt.Sprintf("%s -- %s", track.Name, artistNames(track.Artists))
+}
+
+func artistNames(artists []spotify.Artist) string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// joinNames lists names like the authors or narrators of an audiobook
+func joinNames(names []spotify.Name) string {
+	joined := make([]string, len(names))
+	for i, name := range names {
+		joined[i] = name.Name
+	}
+	return strings.Join(joined, ", ")
+}
+
+// formatDuration formats milliseconds as minutes and seconds, like 3:07
+func formatDuration(ms int) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/mix"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var playlistCommand = &cobra.Command{
+	Use:   "playlist",
+	Short: "Work with your playlists",
+}
+
+var playlistMixCommand = &cobra.Command{
+	Use:   "mix <playlist>",
+	Short: "Order a playlist like a DJ set, by harmonic key, tempo and energy",
+	Long: "Works out each track's key on the Camelot wheel and orders the playlist so that keys mix and tempos change as little as possible, optionally following an energy curve. " +
+		"Prints the new order with every transition and only changes the playlist when run with --apply. " +
+		"Tracks outside --min-bpm and --max-bpm, local files and episodes are moved to the end.",
+	Example: "spotify-cli playlist mix \"Friday Night\"\nspotify-cli playlist mix spotify:playlist:37i9dQZF1DXcBWIGoYBM5M --min-bpm 118 --max-bpm 128 --energy arc --apply",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		minBPM, _ := cmd.Flags().GetFloat64("min-bpm")
+		maxBPM, _ := cmd.Flags().GetFloat64("max-bpm")
+		curve, _ := cmd.Flags().GetString("energy")
+		apply, _ := cmd.Flags().GetBool("apply")
+
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		playlistID, err := resolveUserPlaylist(cmd.Context(), spotifyClient, args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		items, err := spotifyClient.GetPlaylistTracks(cmd.Context(), playlistID)
+		if err != nil {
+			fmt.Println("Failed to get playlist tracks:", err)
+			return
+		}
+		tracks, err := mixTracks(cmd.Context(), spotifyClient, items.Items)
+		if err != nil {
+			fmt.Println("Failed to get audio features:", err)
+			return
+		}
+
+		result, err := mix.Order(tracks, mix.Options{MinBPM: minBPM, MaxBPM: maxBPM, Curve: curve})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		var rows [][]string
+		for i, step := range result.Steps {
+			row := []string{strconv.Itoa(i + 1), step.Track.Name + " -- " + step.Track.Artists, step.Track.Key.String(), formatFloat(step.Track.Tempo, 1), formatFloat(step.Track.Energy, 2), "", ""}
+			if i > 0 {
+				row[5] = step.Transition
+				row[6] = fmt.Sprintf("%+.1f%%", step.TempoChange)
+			}
+			rows = append(rows, row)
+		}
+		for i, track := range result.Excluded {
+			rows = append(rows, []string{strconv.Itoa(len(result.Steps) + i + 1), track.Name + " -- " + track.Artists, "", "", "", "left out", ""})
+		}
+		printOutput(cmd, []string{"#", "Track", "Camelot", "BPM", "Energy", "Transition", "Tempo"}, rows, result)
+
+		if !apply {
+			if format, _ := cmd.Flags().GetString("output"); format == outputText {
+				fmt.Println("Run again with --apply to reorder the playlist")
+			}
+			return
+		}
+		var uris []string
+		for _, track := range result.Tracks() {
+			if strings.HasPrefix(track.URI, "spotify:local:") {
+				fmt.Println("Can't reorder a playlist with local files, since they can't be added back through the API")
+				return
+			}
+			if track.URI == "" {
+				fmt.Println("Can't reorder a playlist with unavailable items, since they would be dropped from it")
+				return
+			}
+			uris = append(uris, track.URI)
+		}
+		if err := spotifyClient.ReplacePlaylistTracks(cmd.Context(), playlistID, uris); err != nil {
+			fmt.Println("Failed to reorder playlist:", err)
+			return
+		}
+		fmt.Println("Reordered", len(uris), "tracks")
+	},
+}
+
+func init() {
+	playlistMixCommand.Flags().Float64("min-bpm", 0, "Lowest tempo to mix, counting half and double time")
+	playlistMixCommand.Flags().Float64("max-bpm", 0, "Highest tempo to mix, counting half and double time")
+	playlistMixCommand.Flags().String("energy", "", "Energy curve to follow: "+strings.Join(mix.Curves, ", "))
+	playlistMixCommand.Flags().Bool("apply", false, "Reorder the playlist instead of just printing the new order")
+	playlistCommand.AddCommand(playlistMixCommand)
+}
+
+// resolveUserPlaylist returns the ID of a playlist given by ID, URI, link or
+// the name of one of the user's own playlists
+func resolveUserPlaylist(ctx context.Context, spotifyClient spotify.Client, playlist string) (string, error) {
+	if id, ok := spotify.ParseID(playlist, "playlist"); ok {
+		return id, nil
+	}
+	playlists, err := spotifyClient.GetCurrentUserPlaylists(ctx)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get playlists: %v", err)
+	}
+	for _, p := range playlists.Items {
+		if strings.EqualFold(p.Name, playlist) {
+			return p.ID, nil
+		}
+	}
+	return "", fmt.Errorf("Couldn't find a playlist called %s", playlist)
+}
+
+// mixTracks looks up the audio features of playlist items to mix them.
+// Unavailable items are kept, without a URI, so they show up as left out.
+func mixTracks(ctx context.Context, spotifyClient spotify.Client, items []spotify.PlaylistTrack) ([]mix.Track, error) {
+	var tracks []mix.Track
+	var ids []string
+	for _, item := range items {
+		if item.Track == nil {
+			tracks = append(tracks, mix.Track{Name: "(unavailable)"})
+			continue
+		}
+		tracks = append(tracks, mix.Track{
+			ID:      item.Track.ID,
+			URI:     item.Track.URI,
+			Name:    item.Track.Name,
+			Artists: artistNames(item.Track.Artists),
+		})
+		if strings.HasPrefix(item.Track.URI, "spotify:track:") {
+			ids = append(ids, item.Track.ID)
+		}
+	}
+
+	out, err := spotifyClient.GetAudioFeatures(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	features := map[string]*spotify.AudioFeatures{}
+	for _, f := range out.AudioFeatures {
+		if f != nil {
+			features[f.ID] = f
+		}
+	}
+	for i, track := range tracks {
+		f, ok := features[track.ID]
+		if !ok {
+			continue
+		}
+		key, ok := mix.FromKey(f.Key, f.Mode)
+		if !ok {
+			continue
+		}
+		tracks[i].Tempo = f.Tempo
+		tracks[i].Energy = f.Energy
+		tracks[i].Key = key
+		tracks[i].HasFeatures = true
+	}
+	return tracks, nil
+}
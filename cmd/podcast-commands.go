@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/feed"
+	"github.com/cwseger/spotify-cli/podcast"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var showCommand = &cobra.Command{
+	Use:   "show <show>",
+	Short: "Get a podcast show and a page of its episodes, with how far you got in each",
+	Long: "Shows can be given by name, ID, URI or link. Episodes are listed newest first, " +
+		"a page at a time with --limit and --offset, along with where you left off listening.",
+	Example: "spotify-cli show Radiolab\n" +
+		"spotify-cli show spotify:show:2mTUnDkuKUkhiueKcVWoP0 --limit 50 --offset 50",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+		market, _ := cmd.Flags().GetString("market")
+		if limit < 1 || limit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			return
+		}
+
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		show, err := spotifyClient.GetShow(cmd.Context(), strings.Join(args, " "), market)
+		if err != nil {
+			fmt.Println("Failed to get show:", err)
+			return
+		}
+		episodes, err := spotifyClient.GetShowEpisodes(cmd.Context(), show.ID, market, limit, offset)
+		if err != nil {
+			fmt.Println("Failed to get episodes:", err)
+			return
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Println("Name:", show.Name)
+			fmt.Println("Publisher:", show.Publisher)
+			fmt.Println("Episodes:", show.TotalEpisodes)
+			if len(show.Languages) > 0 {
+				fmt.Println("Languages:", strings.Join(show.Languages, ", "))
+			}
+			if show.Explicit {
+				fmt.Println("Explicit")
+			}
+			fmt.Println(show.Description)
+			fmt.Println()
+		}
+
+		var rows [][]string
+		for _, episode := range episodes.Items {
+			rows = append(rows, []string{episode.ReleaseDate, episode.Name, formatDuration(episode.DurationMS), formatResumePoint(episode.ResumePoint, episode.DurationMS), episode.URI})
+		}
+		printOutput(cmd, []string{"Released", "Name", "Duration", "Progress", "URI"}, rows, struct {
+			Show     *spotify.Show                  `json:"show"`
+			Episodes *spotify.GetShowEpisodesOutput `json:"episodes"`
+		}{show, episodes})
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText && episodes.Next != "" {
+			fmt.Printf("Showing %d to %d of %d, use --offset %d for more\n", offset+1, offset+len(episodes.Items), episodes.Total, offset+len(episodes.Items))
+		}
+	},
+}
+
+var showSubcommands = []*cobra.Command{
+	{
+		Use:     "saved",
+		Short:   "List the shows in your library",
+		Example: "spotify-cli show saved",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			if err := printLibrary(cmd, spotifyClient, spotify.LibraryShows); err != nil {
+				fmt.Println("Failed to list saved shows:", err)
+			}
+		},
+	},
+	{
+		Use:     "save <show...>",
+		Short:   "Save shows to your library",
+		Example: "spotify-cli show save Radiolab \"99% Invisible\"",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			saveLibraryItems(cmd, spotify.LibraryShows, args)
+		},
+	},
+	{
+		Use:     "remove <show...>",
+		Short:   "Remove shows from your library",
+		Example: "spotify-cli show remove spotify:show:2mTUnDkuKUkhiueKcVWoP0",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			removeLibraryItems(cmd, spotify.LibraryShows, args)
+		},
+	},
+	{
+		Use:   "continue",
+		Short: "List the episodes you started but didn't finish",
+		Long: "Looks through the latest episodes of every show in your library, and the episodes saved on their own, " +
+			"for ones you started listening to but didn't finish, newest first.",
+		Example: "spotify-cli show continue\nspotify-cli show continue --episodes 50",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			perShow, _ := cmd.Flags().GetInt("episodes")
+			if perShow < 1 || perShow > 50 {
+				fmt.Println("Episodes must be between 1 and 50")
+				return
+			}
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			shows, err := spotifyClient.GetSavedShows(cmd.Context())
+			if err != nil {
+				fmt.Println("Failed to get saved shows:", err)
+				return
+			}
+			saved, err := spotifyClient.GetSavedEpisodes(cmd.Context())
+			if err != nil {
+				fmt.Println("Failed to get saved episodes:", err)
+				return
+			}
+
+			var started []spotify.Episode
+			seen := map[string]bool{}
+			add := func(episode spotify.Episode) {
+				if seen[episode.ID] || !inProgress(episode) {
+					return
+				}
+				seen[episode.ID] = true
+				started = append(started, episode)
+			}
+			for _, item := range saved.Items {
+				add(item.Episode)
+			}
+			for _, item := range shows.Items {
+				show := item.Show
+				episodes, err := spotifyClient.GetShowEpisodes(cmd.Context(), show.ID, "", perShow, 0)
+				if err != nil {
+					fmt.Println("Failed to get episodes of "+show.Name+":", err)
+					return
+				}
+				for _, episode := range episodes.Items {
+					episode.Show = &show
+					add(episode)
+				}
+			}
+			sort.SliceStable(started, func(i, j int) bool {
+				return started[i].ReleaseDate > started[j].ReleaseDate
+			})
+
+			var rows [][]string
+			for _, episode := range started {
+				rows = append(rows, []string{episodeShowName(episode), episode.Name, episode.ReleaseDate, formatResumePoint(episode.ResumePoint, episode.DurationMS), formatDuration(episode.DurationMS - episode.ResumePoint.ResumePositionMS), episode.URI})
+			}
+			if format, _ := cmd.Flags().GetString("output"); format == outputText && len(started) == 0 {
+				fmt.Println("Nothing to continue")
+				return
+			}
+			printOutput(cmd, []string{"Show", "Episode", "Released", "Progress", "Left", "URI"}, rows, started)
+		},
+	},
+	{
+		Use:   "feed <show>",
+		Short: "Write a show's episodes as an RSS feed",
+		Long: "Pages through every episode of a show and writes them as RSS 2.0 with their descriptions, release dates, durations, " +
+			"artwork and links to open.spotify.com, so the show can be followed in any feed reader. " +
+			"When --file already holds a feed only the episodes released since it was written are fetched and added to it.",
+		Example: "spotify-cli show feed Radiolab > radiolab.xml\n" +
+			"spotify-cli show feed spotify:show:2mTUnDkuKUkhiueKcVWoP0 --since 2024-01-01 --file radiolab.xml",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, _ := cmd.Flags().GetString("file")
+			market, _ := cmd.Flags().GetString("market")
+			var since time.Time
+			if value, _ := cmd.Flags().GetString("since"); value != "" {
+				day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+				if err != nil {
+					fmt.Println("Since must be a date like 2024-01-31")
+					return
+				}
+				since = day
+			}
+
+			var previous *feed.Feed
+			if path != "" {
+				file, err := os.Open(path)
+				if err != nil && !os.IsNotExist(err) {
+					fmt.Println("Failed to open feed:", err)
+					return
+				}
+				if err == nil {
+					previous, err = feed.ReadRSS(file)
+					file.Close()
+					if err != nil {
+						fmt.Println(err)
+						return
+					}
+				}
+			}
+
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			show, err := spotifyClient.GetShow(cmd.Context(), strings.Join(args, " "), market)
+			if err != nil {
+				fmt.Println("Failed to get show:", err)
+				return
+			}
+			f, added, err := podcast.Feed(cmd.Context(), spotifyClient, show, previous, podcast.Options{Since: since, Market: market})
+			if err != nil {
+				fmt.Println("Failed to get episodes:", err)
+				return
+			}
+
+			if path == "" {
+				if err := feed.WriteRSS(os.Stdout, f); err != nil {
+					fmt.Println(err)
+				}
+				return
+			}
+			if err := writeFeedFile(path, f, feedFormats["rss"]); err != nil {
+				fmt.Println("Failed to write feed:", err)
+				return
+			}
+			fmt.Println("Added", added, "episodes to", path)
+		},
+	},
+}
+
+var episodeCommand = &cobra.Command{
+	Use:     "episode <episode>",
+	Short:   "Get a podcast episode and where you left off in it",
+	Example: "spotify-cli episode spotify:episode:512ojhOuo1ktJprKbVcKyQ",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		market, _ := cmd.Flags().GetString("market")
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		episode, err := spotifyClient.GetEpisode(cmd.Context(), strings.Join(args, " "), market)
+		if err != nil {
+			fmt.Println("Failed to get episode:", err)
+			return
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); format == outputText {
+			fmt.Println("Name:", episode.Name)
+			fmt.Println("Show:", episodeShowName(*episode))
+			fmt.Println("Released:", episode.ReleaseDate)
+			fmt.Println("Duration:", formatDuration(episode.DurationMS))
+			if progress := formatResumePoint(episode.ResumePoint, episode.DurationMS); progress != "" {
+				fmt.Println("Progress:", progress)
+			}
+			if episode.Explicit {
+				fmt.Println("Explicit")
+			}
+			fmt.Println(episode.Description)
+			return
+		}
+		played, position := "", ""
+		if episode.ResumePoint != nil {
+			played = strconv.FormatBool(episode.ResumePoint.FullyPlayed)
+			position = formatDuration(episode.ResumePoint.ResumePositionMS)
+		}
+		printOutput(cmd, []string{"Name", "Show", "Released", "Duration", "Position", "Fully Played", "URI"},
+			[][]string{{episode.Name, episodeShowName(*episode), episode.ReleaseDate, formatDuration(episode.DurationMS), position, played, episode.URI}}, episode)
+	},
+}
+
+var episodeSubcommands = []*cobra.Command{
+	{
+		Use:     "saved",
+		Short:   "List the episodes in your library",
+		Example: "spotify-cli episode saved",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			spotifyClient, err := newUserClient(cmd)
+			if err != nil {
+				fmt.Println("Failed to create new spotify client:", err)
+				return
+			}
+			if err := printLibrary(cmd, spotifyClient, spotify.LibraryEpisodes); err != nil {
+				fmt.Println("Failed to list saved episodes:", err)
+			}
+		},
+	},
+	{
+		Use:     "save <episode...>",
+		Short:   "Save episodes to your library",
+		Example: "spotify-cli episode save spotify:episode:512ojhOuo1ktJprKbVcKyQ",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			saveLibraryItems(cmd, spotify.LibraryEpisodes, args)
+		},
+	},
+	{
+		Use:     "remove <episode...>",
+		Short:   "Remove episodes from your library",
+		Example: "spotify-cli episode remove spotify:episode:512ojhOuo1ktJprKbVcKyQ",
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			removeLibraryItems(cmd, spotify.LibraryEpisodes, args)
+		},
+	},
+}
+
+func init() {
+	showCommand.Flags().Int("limit", 20, "Number of episodes to list, 1 to 50")
+	showCommand.Flags().Int("offset", 0, "Number of newer episodes to skip")
+	showSubcommands[3].Flags().Int("episodes", 20, "Number of the latest episodes of each show to look through, 1 to 50")
+	showSubcommands[4].Flags().String("file", "", "Feed file to write, adding to it if it already exists (default stdout)")
+	showSubcommands[4].Flags().String("since", "", "Leave out episodes released before this day, as YYYY-MM-DD")
+	showCommand.AddCommand(showSubcommands...)
+
+	episodeCommand.AddCommand(episodeSubcommands...)
+}
+
+// inProgress reports whether an episode was started but not finished
+func inProgress(episode spotify.Episode) bool {
+	return episode.ResumePoint != nil && !episode.ResumePoint.FullyPlayed && episode.ResumePoint.ResumePositionMS > 0
+}
+
+// formatResumePoint describes how far the user got in an episode or chapter, like 12:03 / 45:10
+func formatResumePoint(point *spotify.ResumePoint, durationMS int) string {
+	switch {
+	case point == nil:
+		return ""
+	case point.FullyPlayed:
+		return "played"
+	case point.ResumePositionMS > 0:
+		return formatDuration(point.ResumePositionMS) + " / " + formatDuration(durationMS)
+	}
+	return ""
+}
+
+func episodeShowName(episode spotify.Episode) string {
+	if episode.Show == nil {
+		return ""
+	}
+	return episode.Show.Name
+}
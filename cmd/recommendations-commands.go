@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var recommendationsCommand = &cobra.Command{
+	Use:   "recommendations [artist]",
+	Short: "Get recommended tracks from up to five artists, tracks and genres",
+	Long: "Seeds can be mixed freely as long as there are no more than five of them. Artists and tracks can be given by name, ID, URI or link, " +
+		"genres by name as listed by recommendations genres. Tunables narrow the results down, as in --tune min_tempo=120 --tune target_energy=0.8, " +
+		"and can be min_, max_ or target_ followed by one of " + strings.Join(spotify.TunableAttributes, ", ") + ".",
+	Example: "spotify-cli recommendations The Black Keys\n" +
+		"spotify-cli recommendations --artist Khruangbin --track \"Tighten Up\" --genre soul --limit 50 --market US\n" +
+		"spotify-cli recommendations --genre house --tune min_tempo=120 --tune max_tempo=128 --tune target_energy=0.8 --save \"House Picks\"",
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		artists, _ := cmd.Flags().GetStringArray("artist")
+		tracks, _ := cmd.Flags().GetStringArray("track")
+		genres, _ := cmd.Flags().GetStringArray("genre")
+		tunes, _ := cmd.Flags().GetStringArray("tune")
+		limit, _ := cmd.Flags().GetInt("limit")
+		market, _ := cmd.Flags().GetString("market")
+		save, _ := cmd.Flags().GetString("save")
+		public, _ := cmd.Flags().GetBool("public")
+
+		if len(args) > 0 {
+			artists = append([]string{strings.Join(args, " ")}, artists...)
+		}
+		if seeds := len(artists) + len(tracks) + len(genres); seeds == 0 || seeds > 5 {
+			fmt.Println("Must provide between 1 and 5 artists, tracks and genres together")
+			return
+		}
+		if limit < 1 || limit > 100 {
+			fmt.Println("Limit must be between 1 and 100")
+			return
+		}
+		tunables, err := parseTunables(tunes)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		// Saving needs the user's permission, and a user token works for the rest too
+		var spotifyClient spotify.Client
+		if save != "" {
+			spotifyClient, err = newUserClient(cmd)
+		} else {
+			spotifyClient, err = newClient(cmd)
+		}
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+
+		input, err := recommendationSeeds(cmd.Context(), spotifyClient, artists, tracks, genres)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		input.Limit = limit
+		input.Market = market
+		input.Tunables = tunables
+
+		out, err := spotifyClient.GetRecommendations(cmd.Context(), input)
+		if err != nil {
+			fmt.Println("Failed to get recommendations:", err)
+			return
+		}
+
+		var rows [][]string
+		for _, track := range out.Tracks {
+			rows = append(rows, []string{track.Name, artistNames(track.Artists), track.Album.Name, formatDuration(track.DurationMS), strconv.Itoa(track.Popularity), track.URI})
+		}
+		printOutput(cmd, []string{"Name", "Artist", "Album", "Duration", "Popularity", "URI"}, rows, out)
+
+		if save == "" {
+			return
+		}
+		if len(out.Tracks) == 0 {
+			fmt.Println("Nothing to save")
+			return
+		}
+		user, err := spotifyClient.GetCurrentUser(cmd.Context())
+		if err != nil {
+			fmt.Println("Failed to get current user:", err)
+			return
+		}
+		playlist, err := spotifyClient.CreatePlaylist(cmd.Context(), user.ID, &spotify.CreatePlaylistInput{
+			Name:        save,
+			Description: "Recommendations from " + describeSeeds(artists, tracks, genres),
+			Public:      public,
+		})
+		if err != nil {
+			fmt.Println("Failed to create playlist:", err)
+			return
+		}
+		var uris []string
+		for _, track := range out.Tracks {
+			uris = append(uris, track.URI)
+		}
+		if err := spotifyClient.ReplacePlaylistTracks(cmd.Context(), playlist.ID, uris); err != nil {
+			fmt.Println("Failed to add tracks to playlist:", err)
+			return
+		}
+		fmt.Println("Saved", len(uris), "tracks to", save)
+	},
+}
+
+var recommendationGenresCommand = &cobra.Command{
+	Use:     "genres",
+	Short:   "List the genres recommendations can be seeded with",
+	Example: "spotify-cli recommendations genres",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		spotifyClient, err := newClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		out, err := spotifyClient.GetGenreSeeds(cmd.Context())
+		if err != nil {
+			fmt.Println("Failed to get genres:", err)
+			return
+		}
+		var rows [][]string
+		for _, genre := range out.Genres {
+			rows = append(rows, []string{genre})
+		}
+		printOutput(cmd, []string{"Genre"}, rows, out.Genres)
+	},
+}
+
+func init() {
+	recommendationsCommand.Flags().StringArray("artist", nil, "Artist to seed with, can be repeated")
+	recommendationsCommand.Flags().StringArray("track", nil, "Track to seed with, can be repeated")
+	recommendationsCommand.Flags().StringArray("genre", nil, "Genre to seed with, can be repeated")
+	recommendationsCommand.Flags().StringArray("tune", nil, "Tunable as name=value, such as min_tempo=120, can be repeated")
+	recommendationsCommand.Flags().Int("limit", 20, "Number of tracks to get, 1 to 100")
+	recommendationsCommand.Flags().String("save", "", "Save the tracks to a new playlist with this name")
+	recommendationsCommand.Flags().Bool("public", false, "Make the saved playlist public")
+	recommendationsCommand.AddCommand(recommendationGenresCommand)
+}
+
+func parseTunables(tunes []string) (map[string]float64, error) {
+	tunables := map[string]float64{}
+	for _, tune := range tunes {
+		parts := strings.SplitN(tune, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Tunable %q must look like name=value", tune)
+		}
+		name := strings.TrimSpace(parts[0])
+		if !spotify.IsTunable(name) {
+			return nil, fmt.Errorf("Unknown tunable %q, must be min_, max_ or target_ followed by one of %s", name, strings.Join(spotify.TunableAttributes, ", "))
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("Tunable %s must be a number", name)
+		}
+		tunables[name] = value
+	}
+	return tunables, nil
+}
+
+// recommendationSeeds resolves artists and tracks to IDs and checks the
+// genres against the ones Spotify accepts
+func recommendationSeeds(ctx context.Context, spotifyClient spotify.Client, artists, tracks, genres []string) (*spotify.GetRecommendationsInput, error) {
+	input := &spotify.GetRecommendationsInput{}
+	for _, artist := range artists {
+		id, err := spotifyClient.ResolveID(ctx, artist, "artist")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find artist %s: %v", artist, err)
+		}
+		input.SeedArtists = append(input.SeedArtists, id)
+	}
+	for _, track := range tracks {
+		id, err := spotifyClient.ResolveID(ctx, track, "track")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find track %s: %v", track, err)
+		}
+		input.SeedTracks = append(input.SeedTracks, id)
+	}
+	if len(genres) == 0 {
+		return input, nil
+	}
+
+	available, err := spotifyClient.GetGenreSeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, genre := range available.Genres {
+		known[genre] = true
+	}
+	for _, genre := range genres {
+		genre = strings.ToLower(strings.TrimSpace(genre))
+		if !known[genre] {
+			return nil, errors.New("Unknown genre " + genre + ", see recommendations genres for the ones that can be used")
+		}
+		input.SeedGenres = append(input.SeedGenres, genre)
+	}
+	return input, nil
+}
+
+func describeSeeds(artists, tracks, genres []string) string {
+	seeds := append(append(append([]string{}, artists...), tracks...), genres...)
+	return strings.Join(seeds, ", ")
+}
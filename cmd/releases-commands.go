@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cwseger/spotify-cli/feed"
+	"github.com/cwseger/spotify-cli/ical"
+	"github.com/cwseger/spotify-cli/releases"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var feedFormats = map[string]func(*os.File, *feed.Feed) error{
+	"rss": func(file *os.File, f *feed.Feed) error {
+		return feed.WriteRSS(file, f)
+	},
+	"atom": func(file *os.File, f *feed.Feed) error {
+		return feed.WriteAtom(file, f)
+	},
+}
+
+var releasesCommand = &cobra.Command{
+	Use:   "releases",
+	Short: "Keep up with new releases from the artists you care about",
+}
+
+var releasesWatchCommand = &cobra.Command{
+	Use:   "watch",
+	Short: "Report new albums and singles from followed and watchlisted artists",
+	Long: "Checks the artists you follow and the ones in the watchlist file for albums and singles released since the last run. " +
+		"Every release is remembered in the state file so it is only reported once. The first run has nothing to compare against " +
+		"and only records what is out already, unless --since is given. " +
+		"The watchlist has one artist per line as a name, ID, URI or link; lines starting with # are ignored.",
+	Example: "spotify-cli releases watch\n" +
+		"spotify-cli releases watch --since 2024-01-01 --feed releases.xml --feed-format atom\n" +
+		"spotify-cli releases watch --no-followed --watchlist artists.txt --playlist \"New Releases\"",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		noFollowed, _ := cmd.Flags().GetBool("no-followed")
+		watchlistPath, _ := cmd.Flags().GetString("watchlist")
+		statePath, _ := cmd.Flags().GetString("state")
+		market, _ := cmd.Flags().GetString("market")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		feedPath, _ := cmd.Flags().GetString("feed")
+		feedFormat, _ := cmd.Flags().GetString("feed-format")
+		playlist, _ := cmd.Flags().GetString("playlist")
+
+		writeFeed, ok := feedFormats[feedFormat]
+		if !ok {
+			fmt.Println("Feed format must be rss or atom")
+			return
+		}
+		var since time.Time
+		if value, _ := cmd.Flags().GetString("since"); value != "" {
+			day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				fmt.Println("Since must be a date like 2024-01-31")
+				return
+			}
+			since = day
+		}
+		watchlistPath, err := watchlistFile(watchlistPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if statePath == "" {
+			path, err := releases.DefaultStatePath()
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			statePath = path
+		}
+
+		var spotifyClient spotify.Client
+		if noFollowed && playlist == "" {
+			spotifyClient, err = newClient(cmd)
+		} else {
+			spotifyClient, err = newUserClient(cmd)
+		}
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+
+		artists, err := watchedArtists(cmd.Context(), spotifyClient, !noFollowed, watchlistPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(artists) == 0 {
+			fmt.Println("No artists to watch, follow some or add them to", watchlistPath)
+			return
+		}
+		state, err := releases.LoadState(statePath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		firstRun := state.LastRun.IsZero() && since.IsZero()
+
+		found, err := releases.Check(cmd.Context(), spotifyClient, artists, state, releases.Options{
+			Since:       since,
+			Market:      market,
+			Concurrency: concurrency,
+		})
+		if err != nil {
+			fmt.Println("Failed to check for new releases:", err)
+			return
+		}
+
+		if playlist != "" && len(found) > 0 {
+			if err := addReleasesToPlaylist(cmd.Context(), spotifyClient, playlist, found); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if err := state.Save(statePath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if feedPath != "" {
+			if err := writeFeedFile(feedPath, releases.Feed(state), writeFeed); err != nil {
+				fmt.Println("Failed to write feed:", err)
+				return
+			}
+		}
+
+		if firstRun {
+			fmt.Printf("Recorded the releases of %d artists, new ones will be reported from the next run\n", len(artists))
+			return
+		}
+		var rows [][]string
+		for _, release := range found {
+			rows = append(rows, []string{release.Album.ReleaseDate, artistNames(release.Album.Artists), release.Album.Name, release.Album.AlbumGroup, fmt.Sprint(release.Album.TotalTracks), release.Album.URI})
+		}
+		if format, _ := cmd.Flags().GetString("output"); format == outputText && len(found) == 0 {
+			fmt.Println("No new releases")
+			return
+		}
+		printOutput(cmd, []string{"Released", "Artist", "Name", "Group", "Tracks", "URI"}, rows, found)
+	},
+}
+
+var releasesCalendarCommand = &cobra.Command{
+	Use:   "calendar [file]",
+	Short: "Export the release dates of followed and watchlisted artists as an iCalendar file",
+	Long: "Writes an all-day event for every album and single by the artists you follow and the ones in the watchlist file, " +
+		"or by the albums Spotify features as new with --new-releases, linking to the album. " +
+		"Releases Spotify only knows the month or year of are put on the first day of it. " +
+		"Events keep their IDs between exports, so the file can be regenerated and subscribed to. Writes to stdout without a file.",
+	Example: "spotify-cli releases calendar releases.ics\n" +
+		"spotify-cli releases calendar --from 2024-01-01 --no-followed --watchlist artists.txt releases.ics\n" +
+		"spotify-cli releases calendar --new-releases --country GB new-releases.ics",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		newReleases, _ := cmd.Flags().GetBool("new-releases")
+		noFollowed, _ := cmd.Flags().GetBool("no-followed")
+		watchlistPath, _ := cmd.Flags().GetString("watchlist")
+		market, _ := cmd.Flags().GetString("market")
+		country, _ := cmd.Flags().GetString("country")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		name, _ := cmd.Flags().GetString("name")
+
+		from, to, err := dateRange(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		watchlistPath, err = watchlistFile(watchlistPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		var spotifyClient spotify.Client
+		if newReleases || noFollowed {
+			spotifyClient, err = newClient(cmd)
+		} else {
+			spotifyClient, err = newUserClient(cmd)
+		}
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+
+		var found []releases.Release
+		if newReleases {
+			out, err := spotifyClient.GetAllNewReleases(cmd.Context(), country)
+			if err != nil {
+				fmt.Println("Failed to get new releases:", err)
+				return
+			}
+			for _, album := range out.Inner.Items {
+				found = append(found, releases.Release{Album: album})
+			}
+		} else {
+			artists, err := watchedArtists(cmd.Context(), spotifyClient, !noFollowed, watchlistPath)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			if len(artists) == 0 {
+				fmt.Println("No artists to export, follow some or add them to", watchlistPath)
+				return
+			}
+			found, err = releases.Collect(cmd.Context(), spotifyClient, artists, releases.Options{
+				Market:      market,
+				Concurrency: concurrency,
+			})
+			if err != nil {
+				fmt.Println("Failed to get releases:", err)
+				return
+			}
+		}
+
+		calendar := releases.Calendar(name, releases.Between(found, from, to))
+		if len(args) == 0 {
+			if err := ical.Write(os.Stdout, calendar); err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
+		file, err := os.Create(args[0])
+		if err != nil {
+			fmt.Println("Failed to create calendar file:", err)
+			return
+		}
+		defer file.Close()
+		if err := ical.Write(file, calendar); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("Wrote", len(calendar.Events), "releases to", args[0])
+	},
+}
+
+func init() {
+	releasesWatchCommand.Flags().Bool("no-followed", false, "Only check the artists in the watchlist")
+	releasesWatchCommand.Flags().String("watchlist", "", "File with artists to watch besides the followed ones (default watchlist.txt in the user config directory)")
+	releasesWatchCommand.Flags().String("state", "", "File to remember seen releases in (default releases.json in the user config directory)")
+	releasesWatchCommand.Flags().String("since", "", "Report releases from this day on, as YYYY-MM-DD, instead of since the last run")
+	releasesWatchCommand.Flags().Int("concurrency", 4, "How many artists to check at once")
+	releasesWatchCommand.Flags().String("feed", "", "Also write the latest releases to this feed file")
+	releasesWatchCommand.Flags().String("feed-format", "rss", "Feed format: rss or atom")
+	releasesWatchCommand.Flags().String("playlist", "", "Add the tracks of new releases to this playlist")
+	releasesCommand.AddCommand(releasesWatchCommand)
+
+	releasesCalendarCommand.Flags().Bool("new-releases", false, "Export the albums Spotify features as new instead of the watched artists")
+	releasesCalendarCommand.Flags().String("country", "", "Country to get new releases for, as an ISO 3166-1 alpha-2 code")
+	releasesCalendarCommand.Flags().Bool("no-followed", false, "Only export the artists in the watchlist")
+	releasesCalendarCommand.Flags().String("watchlist", "", "File with artists to export besides the followed ones (default watchlist.txt in the user config directory)")
+	releasesCalendarCommand.Flags().Int("concurrency", 4, "How many artists to look up at once")
+	releasesCalendarCommand.Flags().String("from", "", "First release day to export, as YYYY-MM-DD")
+	releasesCalendarCommand.Flags().String("to", "", "Last release day to export, as YYYY-MM-DD")
+	releasesCalendarCommand.Flags().String("name", "Spotify releases", "Name of the calendar")
+	releasesCommand.AddCommand(releasesCalendarCommand)
+}
+
+// watchlistFile is the watchlist given by flag, or the default one
+func watchlistFile(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return releases.DefaultWatchlistPath()
+}
+
+// watchedArtists combines the followed artists with the watchlist, once each
+func watchedArtists(ctx context.Context, spotifyClient spotify.Client, followed bool, watchlistPath string) ([]releases.Artist, error) {
+	var artists []releases.Artist
+	seen := map[string]bool{}
+	if followed {
+		out, err := spotifyClient.GetFollowedArtists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get followed artists: %v", err)
+		}
+		for _, artist := range out.Inner.Items {
+			seen[artist.ID] = true
+			artists = append(artists, releases.Artist{ID: artist.ID, Name: artist.Name})
+		}
+	}
+
+	watchlist, err := releases.ReadWatchlist(watchlistPath)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range watchlist {
+		id, err := spotifyClient.ResolveID(ctx, entry, "artist")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find artist %s: %v", entry, err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return artists, nil
+	}
+	out, err := spotifyClient.GetSeveralArtists(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get watchlisted artists: %v", err)
+	}
+	for _, artist := range out.Artists {
+		if artist != nil {
+			artists = append(artists, releases.Artist{ID: artist.ID, Name: artist.Name})
+		}
+	}
+	return artists, nil
+}
+
+func addReleasesToPlaylist(ctx context.Context, spotifyClient spotify.Client, playlist string, found []releases.Release) error {
+	playlistID, err := resolveUserPlaylist(ctx, spotifyClient, playlist)
+	if err != nil {
+		return err
+	}
+	var uris []string
+	for _, release := range found {
+		tracks, err := spotifyClient.GetAlbumTracks(ctx, release.Album.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to get tracks of %s: %v", release.Album.Name, err)
+		}
+		for _, track := range tracks.Tracks {
+			uris = append(uris, track.URI)
+		}
+	}
+	if err := spotifyClient.AddPlaylistTracks(ctx, playlistID, uris); err != nil {
+		return fmt.Errorf("Failed to add new releases to playlist: %v", err)
+	}
+	return nil
+}
+
+func writeFeedFile(path string, f *feed.Feed, write func(*os.File, *feed.Feed) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -8,8 +8,16 @@ import (
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "spotify-cli",
-	Short: "This CLI allows you to interact with Spotify via the command line",
+	Use:               "spotify-cli",
+	Short:             "This CLI allows you to interact with Spotify via the command line",
+	PersistentPreRunE: checkGlobalFlags,
+}
+
+func checkGlobalFlags(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(cmd, args); err != nil {
+		return err
+	}
+	return resolveMarket(cmd)
 }
 
 // Execute -
@@ -18,6 +26,31 @@ func Execute() {
 	rootCmd.AddCommand(artistCommands...)
 	rootCmd.AddCommand(categoryCommands...)
 	rootCmd.AddCommand(commands...)
+	rootCmd.AddCommand(authCommands...)
+	rootCmd.AddCommand(smartCommand)
+	rootCmd.AddCommand(libraryCommand)
+	rootCmd.AddCommand(backupCommands...)
+	rootCmd.AddCommand(followCommand, unfollowCommand, followingCommand)
+	rootCmd.AddCommand(topCommand)
+	rootCmd.AddCommand(historyCommand)
+	rootCmd.AddCommand(statsCommand)
+	rootCmd.AddCommand(audioCommands...)
+	rootCmd.AddCommand(playlistCommand)
+	rootCmd.AddCommand(recommendationsCommand)
+	rootCmd.AddCommand(releasesCommand)
+	rootCmd.AddCommand(showCommand)
+	rootCmd.AddCommand(episodeCommand)
+	rootCmd.AddCommand(audiobookCommand)
+	rootCmd.AddCommand(chapterCommand)
+	rootCmd.AddCommand(availabilityCommand)
+	rootCmd.AddCommand(configCommand)
+	rootCmd.AddCommand(lookupCommand)
+	rootCmd.AddCommand(localCommand)
+	rootCmd.AddCommand(artCommand)
+	rootCmd.AddCommand(nowCommand)
+
+	rootCmd.PersistentFlags().StringP("output", "o", outputText, "Output format: text, json or csv")
+	rootCmd.PersistentFlags().String("market", "", "Country to get catalog items in, as an ISO 3166-1 alpha-2 code such as SE, or from_token for your account's country (default from config)")
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err, "Failed to execute context")
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cwseger/spotify-cli/smart"
+	"github.com/cwseger/spotify-cli/spotify"
+	cobra "github.com/spf13/cobra"
+)
+
+var smartCommand = &cobra.Command{
+	Use:   "smart",
+	Short: "Manage rule-based smart playlists",
+}
+
+var smartSyncCommand = &cobra.Command{
+	Use:     "sync <file>",
+	Short:   "Evaluate a smart playlist definition and write the result to its target playlist",
+	Example: "spotify-cli smart sync chill.yaml --dry-run",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		def, err := smart.Load(args[0])
+		if err != nil {
+			fmt.Println("Failed to load smart playlist:", err)
+			return
+		}
+		spotifyClient, err := newUserClient(cmd)
+		if err != nil {
+			fmt.Println("Failed to create new spotify client:", err)
+			return
+		}
+		out, err := smart.Sync(cmd.Context(), spotifyClient, def, dryRun)
+		if err != nil {
+			fmt.Println("Failed to sync smart playlist:", err)
+			return
+		}
+
+		for _, track := range out.Diff.Added {
+			fmt.Println("+", formatTrack(track))
+		}
+		for _, track := range out.Diff.Removed {
+			fmt.Println("-", formatTrack(track))
+		}
+		fmt.Println(fmt.Sprintf("%s: %d tracks, %d added, %d removed", out.PlaylistName, len(out.Tracks), len(out.Diff.Added), len(out.Diff.Removed)))
+		switch {
+		case out.Diff.Unchanged():
+			fmt.Println("Already up to date")
+		case dryRun:
+			if out.Diff.Reordered {
+				fmt.Println("Order would change")
+			}
+			fmt.Println("Dry run, nothing was changed")
+		case out.Created:
+			fmt.Println("Created playlist", spotify.URI("playlist", out.PlaylistID))
+		case out.Applied:
+			fmt.Println("Updated playlist", spotify.URI("playlist", out.PlaylistID))
+		}
+	},
+}
+
+func init() {
+	smartSyncCommand.Flags().Bool("dry-run", false, "Preview the changes without updating the playlist")
+	smartCommand.AddCommand(smartSyncCommand)
+}
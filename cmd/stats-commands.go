@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	"github.com/cwseger/spotify-cli/stats"
+	cobra "github.com/spf13/cobra"
+)
+
+var statsCommand = &cobra.Command{
+	Use:     "stats",
+	Short:   "Report on your listening from the local history",
+	Long:    "Works out plays and minutes per artist, album, track and genre, when you listen, streaks and how much is new to you, from the history kept by the history commands.",
+	Example: "spotify-cli stats --from 2024-06-01 --to 2024-08-31\nspotify-cli stats --year 2024 --html report.html\nspotify-cli stats -o json",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := dateRange(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		report, err := buildStats(cmd, from, to)
+		if err != nil {
+			fmt.Println("Failed to build stats:", err)
+			return
+		}
+		printStats(cmd, report, stats.RenderText)
+	},
+}
+
+var statsReviewCommand = &cobra.Command{
+	Use:     "review [year]",
+	Short:   "Sum up a year of listening, this year by default",
+	Example: "spotify-cli stats review 2024\nspotify-cli stats review --from 2024-06-01 --to 2024-08-31",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := dateRange(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(args) > 0 {
+			year, err := strconv.Atoi(args[0])
+			if err != nil {
+				fmt.Println("Year must be a number")
+				return
+			}
+			from, to = yearRange(year)
+		} else if from.IsZero() && to.IsZero() {
+			from, to = yearRange(time.Now().Year())
+		}
+
+		report, err := buildStats(cmd, from, to)
+		if err != nil {
+			fmt.Println("Failed to build stats:", err)
+			return
+		}
+		printStats(cmd, report, stats.RenderReview)
+	},
+}
+
+func init() {
+	statsCommand.PersistentFlags().String("dir", "", "Directory the history is kept in (default history in the user config directory)")
+	statsCommand.PersistentFlags().String("from", "", "First day to include, as YYYY-MM-DD (default the start of the history)")
+	statsCommand.PersistentFlags().String("to", "", "Last day to include, as YYYY-MM-DD (default today)")
+	statsCommand.PersistentFlags().Int("year", 0, "Report on a whole calendar year instead of --from and --to")
+	statsCommand.PersistentFlags().Int("top", 10, "Number of artists, albums, tracks and genres to list, 0 for all")
+	statsCommand.PersistentFlags().String("html", "", "Write a self-contained HTML report to this file instead")
+	statsCommand.PersistentFlags().Bool("no-genres", false, "Skip looking up artist genres on Spotify")
+	statsCommand.AddCommand(statsReviewCommand)
+}
+
+// dateRange reads the range picked with --year or --from and --to, with to
+// being the exclusive end so that --to covers the whole of its day
+func dateRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	year, _ := cmd.Flags().GetInt("year")
+	if year != 0 {
+		from, to := yearRange(year)
+		return from, to, nil
+	}
+
+	var from, to time.Time
+	if value, _ := cmd.Flags().GetString("from"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("From must be a date like 2024-01-31")
+		}
+		from = day
+	}
+	if value, _ := cmd.Flags().GetString("to"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("To must be a date like 2024-12-31")
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("From must be before to")
+	}
+	return from, to, nil
+}
+
+func yearRange(year int) (time.Time, time.Time) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	return from, from.AddDate(1, 0, 0)
+}
+
+func buildStats(cmd *cobra.Command, from, to time.Time) (*stats.Report, error) {
+	top, _ := cmd.Flags().GetInt("top")
+	noGenres, _ := cmd.Flags().GetBool("no-genres")
+
+	store, err := openHistory(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var lookup stats.GenreLookup
+	if !noGenres {
+		spotifyClient, err := newClient(cmd)
+		if err != nil {
+			return nil, err
+		}
+		lookup = artistGenres(cmd.Context(), spotifyClient)
+	}
+	return stats.Build(store, from, to, top, lookup)
+}
+
+func artistGenres(ctx context.Context, spotifyClient spotify.Client) stats.GenreLookup {
+	return func(ids []string) (map[string][]string, error) {
+		out, err := spotifyClient.GetSeveralArtists(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		genres := map[string][]string{}
+		for _, artist := range out.Artists {
+			if artist != nil {
+				genres[artist.ID] = artist.Genres
+			}
+		}
+		return genres, nil
+	}
+}
+
+// printStats prints the report with render for text output, or writes it
+// to the --html file. CSV output flattens the rankings and breakdowns into
+// one table.
+func printStats(cmd *cobra.Command, report *stats.Report, render func(io.Writer, *stats.Report) error) {
+	if path, _ := cmd.Flags().GetString("html"); path != "" {
+		if err := writeHTMLStats(path, report); err != nil {
+			fmt.Println("Failed to write HTML report:", err)
+			return
+		}
+		fmt.Println("Wrote report to", path)
+		return
+	}
+
+	format, _ := cmd.Flags().GetString("output")
+	if format == outputText {
+		if err := render(os.Stdout, report); err != nil {
+			fmt.Println("Failed to print stats:", err)
+		}
+		return
+	}
+
+	var rows [][]string
+	add := func(section string, counts []stats.Count) {
+		for _, count := range counts {
+			rows = append(rows, []string{section, count.Name, count.Detail, strconv.Itoa(count.Plays), strconv.FormatFloat(count.Minutes, 'f', 1, 64)})
+		}
+	}
+	add("artist", report.Artists)
+	add("album", report.Albums)
+	add("track", report.Tracks)
+	add("genre", report.Genres)
+	add("hour", report.Hours[:])
+	add("weekday", report.Weekdays[:])
+	add("month", report.Months)
+	printOutput(cmd, []string{"Section", "Name", "Detail", "Plays", "Minutes"}, rows, report)
+}
+
+func writeHTMLStats(path string, report *stats.Report) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := stats.RenderHTML(file, report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
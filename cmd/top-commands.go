@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cwseger/spotify-cli/spotify"
+	"github.com/cwseger/spotify-cli/top"
+	cobra "github.com/spf13/cobra"
+)
+
+var timeRanges = map[string]string{
+	"short":  spotify.TimeRangeShort,
+	"medium": spotify.TimeRangeMedium,
+	"long":   spotify.TimeRangeLong,
+}
+
+var topCommand = &cobra.Command{
+	Use:   "top",
+	Short: "Get your top artists and tracks",
+}
+
+var topCommands = []*cobra.Command{
+	{
+		Use:     "artists",
+		Short:   "Get your top artists over the last 4 weeks (short), 6 months (medium) or year (long)",
+		Example: "spotify-cli top artists --range short --limit 50\nspotify-cli top artists --compare",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runTop(cmd, "artists")
+		},
+	},
+	{
+		Use:     "tracks",
+		Short:   "Get your top tracks over the last 4 weeks (short), 6 months (medium) or year (long)",
+		Example: "spotify-cli top tracks --range long\nspotify-cli top tracks --compare",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runTop(cmd, "tracks")
+		},
+	},
+}
+
+func init() {
+	for _, command := range topCommands {
+		command.Flags().String("range", "medium", "Time range: short, medium or long")
+		command.Flags().Int("limit", 20, "Number of items to get")
+		command.Flags().Bool("compare", false, "Show how the short term ranking moved against the medium and long term ones")
+		topCommand.AddCommand(command)
+	}
+}
+
+func runTop(cmd *cobra.Command, itemType string) {
+	rangeName, _ := cmd.Flags().GetString("range")
+	limit, _ := cmd.Flags().GetInt("limit")
+	compare, _ := cmd.Flags().GetBool("compare")
+
+	timeRange, ok := timeRanges[rangeName]
+	if !ok {
+		fmt.Println("Range must be short, medium or long")
+		return
+	}
+	spotifyClient, err := newUserClient(cmd)
+	if err != nil {
+		fmt.Println("Failed to create new spotify client:", err)
+		return
+	}
+
+	if !compare {
+		items, rows, raw, err := getTopItems(cmd.Context(), spotifyClient, itemType, timeRange, limit)
+		if err != nil {
+			fmt.Println("Failed to get top "+itemType+":", err)
+			return
+		}
+		for i := range items {
+			rows[i] = append([]string{strconv.Itoa(i + 1)}, rows[i]...)
+		}
+		headers := []string{"Rank", "Name", "Popularity", "URI"}
+		if itemType == "tracks" {
+			headers = []string{"Rank", "Name", "Artist", "URI"}
+		}
+		printOutput(cmd, headers, rows, raw)
+		return
+	}
+
+	lists := map[string][]top.Item{}
+	for _, timeRange := range []string{spotify.TimeRangeShort, spotify.TimeRangeMedium, spotify.TimeRangeLong} {
+		items, _, _, err := getTopItems(cmd.Context(), spotifyClient, itemType, timeRange, limit)
+		if err != nil {
+			fmt.Println("Failed to get top "+itemType+":", err)
+			return
+		}
+		lists[timeRange] = items
+	}
+	comparison := top.Compare(lists[spotify.TimeRangeShort], lists[spotify.TimeRangeMedium], lists[spotify.TimeRangeLong])
+
+	var rows [][]string
+	for _, entry := range append(comparison.Entries, comparison.Dropped...) {
+		rows = append(rows, []string{formatRank(entry.Short), entry.Item.Name, formatRank(entry.Medium), formatRank(entry.Long), formatMovement(entry)})
+	}
+	printOutput(cmd, []string{"Short", "Name", "Medium", "Long", "Movement"}, rows, comparison)
+}
+
+// getTopItems returns the top items as rankable items along with a row for each and the raw response
+func getTopItems(ctx context.Context, spotifyClient spotify.Client, itemType, timeRange string, limit int) ([]top.Item, [][]string, interface{}, error) {
+	var items []top.Item
+	var rows [][]string
+	if itemType == "artists" {
+		out, err := spotifyClient.GetTopArtists(ctx, timeRange, limit)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, artist := range out.Items {
+			items = append(items, top.Item{ID: artist.ID, Name: artist.Name, URI: artist.URI})
+			rows = append(rows, []string{artist.Name, strconv.Itoa(artist.Popularity), artist.URI})
+		}
+		return items, rows, out.Items, nil
+	}
+
+	out, err := spotifyClient.GetTopTracks(ctx, timeRange, limit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, track := range out.Items {
+		items = append(items, top.Item{ID: track.ID, Name: track.Name, URI: track.URI})
+		rows = append(rows, []string{track.Name, artistNames(track.Artists), track.URI})
+	}
+	return items, rows, out.Items, nil
+}
+
+func formatRank(rank int) string {
+	if rank == 0 {
+		return "-"
+	}
+	return strconv.Itoa(rank)
+}
+
+func formatMovement(entry top.Entry) string {
+	switch entry.Movement {
+	case top.MovementUp:
+		return fmt.Sprintf("up %d", entry.Change)
+	case top.MovementDown:
+		return fmt.Sprintf("down %d", -entry.Change)
+	}
+	return entry.Movement
+}
@@ -0,0 +1,311 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Feed is what RSS and Atom have in common
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Updated     time.Time
+	// Image is the URL of the feed's artwork
+	Image string
+	Items []Item
+}
+
+// Item is an entry in a feed. ID must stay the same for as long as the item
+// is in the feed so readers only show it once.
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+	// Duration is how long the item plays for, if it's audio or video
+	Duration time.Duration
+	// Image is the URL of the item's own artwork
+	Image     string
+	Enclosure *Enclosure
+}
+
+// Enclosure is a file attached to an item, such as a podcast episode
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// itunesNamespace is where the elements podcast apps read durations and artwork from are defined
+const itunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	ITunes  string     `xml:"xmlns:itunes,attr,omitempty"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string       `xml:"title"`
+	Link          string       `xml:"link"`
+	Description   string       `xml:"description"`
+	LastBuildDate string       `xml:"lastBuildDate,omitempty"`
+	Generator     string       `xml:"generator"`
+	Image         *rssImage    `xml:"image"`
+	ITunesImage   *itunesImage `xml:"itunes:image"`
+	Items         []rssItem    `xml:"item"`
+}
+
+type rssImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssItem struct {
+	Title          string        `xml:"title"`
+	Link           string        `xml:"link,omitempty"`
+	Description    string        `xml:"description,omitempty"`
+	GUID           rssGUID       `xml:"guid"`
+	PubDate        string        `xml:"pubDate,omitempty"`
+	Enclosure      *rssEnclosure `xml:"enclosure"`
+	ITunesDuration string        `xml:"itunes:duration,omitempty"`
+	ITunesImage    *itunesImage  `xml:"itunes:image"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int64  `xml:"length,attr"`
+}
+
+// WriteRSS writes the feed as RSS 2.0
+func WriteRSS(w io.Writer, f *Feed) error {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Generator:   "spotify-cli",
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	if f.Image != "" {
+		doc.ITunes = itunesNamespace
+		doc.Channel.Image = &rssImage{URL: f.Image, Title: f.Title, Link: f.Link}
+		doc.Channel.ITunesImage = &itunesImage{Href: f.Image}
+	}
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			GUID:        rssGUID{Value: item.ID},
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.UTC().Format(time.RFC1123Z)
+		}
+		if item.Enclosure != nil {
+			entry.Enclosure = &rssEnclosure{URL: item.Enclosure.URL, Type: item.Enclosure.Type, Length: item.Enclosure.Length}
+		}
+		if item.Duration > 0 {
+			doc.ITunes = itunesNamespace
+			entry.ITunesDuration = formatDuration(item.Duration)
+		}
+		if item.Image != "" {
+			doc.ITunes = itunesNamespace
+			entry.ITunesImage = &itunesImage{Href: item.Image}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+	return writeXML(w, doc)
+}
+
+// formatDuration writes a duration as hours, minutes and seconds, like 1:02:03
+func formatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// parseDuration reads durations as podcast feeds write them, which is either
+// seconds or colon separated hours, minutes and seconds
+func parseDuration(value string) time.Duration {
+	var seconds int
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// readRSS mirrors rss for decoding, which matches namespaced elements by
+// their namespace rather than the prefix they're written with
+type readRSS struct {
+	Channel struct {
+		Title         string `xml:"title"`
+		Link          string `xml:"link"`
+		Description   string `xml:"description"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Image         struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Items []struct {
+			Title          string        `xml:"title"`
+			Link           string        `xml:"link"`
+			Description    string        `xml:"description"`
+			GUID           string        `xml:"guid"`
+			PubDate        string        `xml:"pubDate"`
+			Enclosure      *rssEnclosure `xml:"enclosure"`
+			ITunesDuration string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+			ITunesImage    itunesImage   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// ReadRSS reads an RSS 2.0 feed, such as one written by WriteRSS so that it
+// can be added to. Dates that can't be parsed are left zero.
+func ReadRSS(r io.Reader) (*Feed, error) {
+	var doc readRSS
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.WithMessage(err, "Failed to decode feed")
+	}
+	f := &Feed{
+		Title:       doc.Channel.Title,
+		Link:        doc.Channel.Link,
+		Description: doc.Channel.Description,
+		Updated:     parseDate(doc.Channel.LastBuildDate),
+		Image:       doc.Channel.Image.URL,
+	}
+	for _, entry := range doc.Channel.Items {
+		item := Item{
+			ID:          entry.GUID,
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Description,
+			Published:   parseDate(entry.PubDate),
+			Duration:    parseDuration(entry.ITunesDuration),
+			Image:       entry.ITunesImage.Href,
+		}
+		if entry.Enclosure != nil {
+			item.Enclosure = &Enclosure{URL: entry.Enclosure.URL, Type: entry.Enclosure.Type, Length: entry.Enclosure.Length}
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+func parseDate(value string) time.Time {
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+type atom struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Logo      string      `xml:"logo,omitempty"`
+	Updated   string      `xml:"updated"`
+	Link      []atomLink  `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published,omitempty"`
+	Link      []atomLink `xml:"link"`
+	Summary   string     `xml:"summary,omitempty"`
+}
+
+// WriteAtom writes the feed as Atom 1.0. Atom needs every entry to have an
+// updated time, so entries without one use the feed's.
+func WriteAtom(w io.Writer, f *Feed) error {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	doc := atom{
+		ID:        f.Link,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Logo:      f.Image,
+		Updated:   updated.UTC().Format(time.RFC3339),
+		Generator: "spotify-cli",
+	}
+	if f.Link != "" {
+		doc.Link = append(doc.Link, atomLink{Href: f.Link})
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: doc.Updated,
+			Summary: item.Description,
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.UTC().Format(time.RFC3339)
+			entry.Updated = entry.Published
+		}
+		if item.Link != "" {
+			entry.Link = append(entry.Link, atomLink{Href: item.Link})
+		}
+		if item.Enclosure != nil {
+			entry.Link = append(entry.Link, atomLink{Href: item.Enclosure.URL, Rel: "enclosure", Type: item.Enclosure.Type, Length: item.Enclosure.Length})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithMessage(err, "Failed to write feed")
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return errors.WithMessage(err, "Failed to encode feed")
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errors.WithMessage(err, "Failed to write feed")
+	}
+	return nil
+}
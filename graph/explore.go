@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/cwseger/spotify-cli/parallel"
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+// Explore maps the artists related to the one given by ID, breadth first,
+// up to options.Depth hops away. Every explored artist links to all of its
+// related artists, but artists at the last hop aren't explored themselves,
+// so links between them are missing.
+func Explore(ctx context.Context, client spotify.Client, artistID string, options Options) (*Graph, error) {
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+	out, err := client.GetSeveralArtists(ctx, []string{artistID})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Artists) == 0 || out.Artists[0] == nil {
+		return nil, errors.Errorf("No artist found for %s", artistID)
+	}
+
+	g := &Graph{Directed: true}
+	seen := map[string]bool{artistID: true}
+	linked := map[Link]bool{}
+	g.Nodes = append(g.Nodes, newNode(*out.Artists[0], 0))
+	frontier := []string{artistID}
+
+	for depth := 1; depth <= options.Depth && len(frontier) > 0; depth++ {
+		related, err := fetchRelated(ctx, client, frontier, options.Concurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for i, source := range frontier {
+			for _, artist := range related[i] {
+				if !seen[artist.ID] {
+					// Artists past the limit are left out along with their links
+					if options.MaxArtists > 0 && len(g.Nodes) >= options.MaxArtists {
+						continue
+					}
+					seen[artist.ID] = true
+					g.Nodes = append(g.Nodes, newNode(artist, depth))
+					next = append(next, artist.ID)
+				}
+				link := Link{Source: source, Target: artist.ID}
+				if !linked[link] {
+					linked[link] = true
+					g.Links = append(g.Links, link)
+				}
+			}
+		}
+		frontier = next
+	}
+	return g, nil
+}
+
+// fetchRelated gets the related artists of every artist in ids, at most
+// concurrency at a time, returning them in the same order as ids
+func fetchRelated(ctx context.Context, client spotify.Client, ids []string, concurrency int) ([][]spotify.Artist, error) {
+	related := make([][]spotify.Artist, len(ids))
+	if err := parallel.ForEach(ctx, len(ids), concurrency, func(ctx context.Context, i int) error {
+		out, err := client.GetRelatedArtists(ctx, ids[i])
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to get artists related to %s", ids[i])
+		}
+		related[i] = out.Artists
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
+
+func newNode(artist spotify.Artist, depth int) Node {
+	genres := artist.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+	return Node{
+		ID:         artist.ID,
+		Name:       artist.Name,
+		URI:        artist.URI,
+		Genres:     genres,
+		Popularity: artist.Popularity,
+		Followers:  artist.Followers.Total,
+		Depth:      depth,
+	}
+}
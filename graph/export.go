@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Formats the graph can be written in
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+	FormatJSON    = "json"
+)
+
+// Write writes the graph in one of the formats
+func Write(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	case FormatJSON:
+		return WriteJSON(w, g)
+	}
+	return errors.Errorf("Unknown graph format %q, must be dot, graphml or json", format)
+}
+
+// WriteDOT writes the graph for Graphviz, with the attributes of each artist
+// on its node and the first artist drawn as a double circle
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph related {\n")
+	b.WriteString("  node [shape=ellipse];\n")
+	for _, node := range g.Nodes {
+		shape := ""
+		if node.Depth == 0 {
+			shape = ", shape=doublecircle"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, uri=%s, genres=%s, popularity=%d, followers=%d, depth=%d%s];\n",
+			dotQuote(node.ID), dotQuote(node.Name), dotQuote(node.URI), dotQuote(strings.Join(node.Genres, ", ")), node.Popularity, node.Followers, node.Depth, shape)
+	}
+	for _, link := range g.Links {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(link.Source), dotQuote(link.Target))
+	}
+	b.WriteString("}\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errors.WithMessage(err, "Failed to write graph")
+	}
+	return nil
+}
+
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
+
+// WriteJSON writes the graph in node-link format
+func WriteJSON(w io.Writer, g *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g); err != nil {
+		return errors.WithMessage(err, "Failed to encode graph")
+	}
+	return nil
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as GraphML, with each artist's attributes as node data
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "uri", For: "node", Name: "uri", Type: "string"},
+			{ID: "genres", For: "node", Name: "genres", Type: "string"},
+			{ID: "popularity", For: "node", Name: "popularity", Type: "int"},
+			{ID: "followers", For: "node", Name: "followers", Type: "int"},
+			{ID: "depth", For: "node", Name: "depth", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "related", EdgeDefault: "directed"},
+	}
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.ID,
+			Data: []graphMLData{
+				{Key: "name", Value: node.Name},
+				{Key: "uri", Value: node.URI},
+				{Key: "genres", Value: strings.Join(node.Genres, ", ")},
+				{Key: "popularity", Value: strconv.Itoa(node.Popularity)},
+				{Key: "followers", Value: strconv.Itoa(node.Followers)},
+				{Key: "depth", Value: strconv.Itoa(node.Depth)},
+			},
+		})
+	}
+	for _, link := range g.Links {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: link.Source, Target: link.Target})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithMessage(err, "Failed to write graph")
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return errors.WithMessage(err, "Failed to encode graph")
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errors.WithMessage(err, "Failed to write graph")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package graph
+
+// Node is an artist in the graph
+type Node struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	URI        string   `json:"uri"`
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+	Followers  int      `json:"followers"`
+	// Depth is how many hops the artist is from the one the graph started at
+	Depth int `json:"depth"`
+}
+
+// Link says that Spotify lists Target as related to Source
+type Link struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Graph is laid out like the node-link format of NetworkX and D3
+type Graph struct {
+	Directed   bool   `json:"directed"`
+	Multigraph bool   `json:"multigraph"`
+	Nodes      []Node `json:"nodes"`
+	Links      []Link `json:"links"`
+}
+
+// Options -
+type Options struct {
+	// Depth is how many hops from the first artist to explore
+	Depth int
+	// Concurrency is how many artists' related artists are fetched at once
+	Concurrency int
+	// MaxArtists stops adding artists once there are this many, zero for no limit
+	MaxArtists int
+}
@@ -0,0 +1,35 @@
+package history
+
+import "time"
+
+// Play is a single listen as kept in the history
+type Play struct {
+	PlayedAt    time.Time `json:"played_at"`
+	TrackID     string    `json:"track_id"`
+	TrackName   string    `json:"track_name"`
+	TrackURI    string    `json:"track_uri"`
+	DurationMS  int       `json:"duration_ms"`
+	AlbumID     string    `json:"album_id"`
+	AlbumName   string    `json:"album_name"`
+	Artists     []Artist  `json:"artists"`
+	ContextType string    `json:"context_type,omitempty"`
+	ContextURI  string    `json:"context_uri,omitempty"`
+}
+
+// Artist -
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Index lets the store skip straight to a day instead of reading every play
+// before it, and remembers the latest play to deduplicate against.
+type Index struct {
+	Count  int       `json:"count"`
+	Latest time.Time `json:"latest"`
+	// Size is how many bytes of the plays file the index covers. A mismatch
+	// means the file was changed behind the store's back and the index is rebuilt.
+	Size int64 `json:"size"`
+	// Days maps each UTC date to the offset of its first play
+	Days map[string]int64 `json:"days"`
+}
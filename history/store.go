@@ -0,0 +1,237 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	playsFile = "plays.jsonl"
+	indexFile = "index.json"
+	dayFormat = "2006-01-02"
+)
+
+// Store is an append-only log of plays, one JSON object per line in the order
+// they were played, along with an index over it.
+type Store struct {
+	dir   string
+	index Index
+}
+
+// DefaultDir is where the history is kept unless told otherwise
+func DefaultDir() (string, error) {
+	dir, err := spotify.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+// Open opens the store in dir, creating it if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.WithMessage(err, "Failed to create history directory")
+	}
+	s := &Store{dir: dir}
+
+	size, err := s.playsSize()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, indexFile))
+	if err == nil && json.Unmarshal(data, &s.index) == nil && s.index.Size == size && s.index.Days != nil {
+		return s, nil
+	}
+	if err := s.rebuild(); err != nil {
+		return nil, errors.WithMessage(err, "Failed to rebuild history index")
+	}
+	return s, nil
+}
+
+// Count is the number of plays in the store
+func (s *Store) Count() int {
+	return s.index.Count
+}
+
+// Latest is when the most recent play in the store started
+func (s *Store) Latest() time.Time {
+	return s.index.Latest
+}
+
+// Append adds the plays that are newer than the latest one in the store and
+// returns how many that was. Plays already in the store are recognised by
+// their played_at time.
+func (s *Store) Append(plays []Play) (int, error) {
+	sort.Slice(plays, func(i, j int) bool {
+		return plays[i].PlayedAt.Before(plays[j].PlayedAt)
+	})
+
+	file, err := os.OpenFile(filepath.Join(s.dir, playsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, errors.WithMessage(err, "Failed to open history")
+	}
+	defer file.Close()
+
+	added := 0
+	for _, play := range plays {
+		if !play.PlayedAt.After(s.index.Latest) {
+			continue
+		}
+		line, err := json.Marshal(play)
+		if err != nil {
+			return added, errors.WithMessage(err, "Failed to marshal play")
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return added, errors.WithMessage(err, "Failed to write play")
+		}
+		s.indexPlay(play, int64(len(line)+1))
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.saveIndex()
+}
+
+// Read calls fn with every play that started in [from, to), oldest first. A
+// zero from or to leaves that end open.
+func (s *Store) Read(from, to time.Time, fn func(Play) error) error {
+	file, err := os.Open(filepath.Join(s.dir, playsFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, "Failed to open history")
+	}
+	defer file.Close()
+
+	if offset, ok := s.offsetOf(from); ok {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return errors.WithMessage(err, "Failed to seek history")
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var play Play
+		if err := json.Unmarshal(scanner.Bytes(), &play); err != nil {
+			return errors.WithMessage(err, "Failed to unmarshal play")
+		}
+		if !from.IsZero() && play.PlayedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !play.PlayedAt.Before(to) {
+			return nil
+		}
+		if err := fn(play); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.WithMessage(err, "Failed to read history")
+	}
+	return nil
+}
+
+// offsetOf finds where the first play on or after t's day starts
+func (s *Store) offsetOf(t time.Time) (int64, bool) {
+	if t.IsZero() {
+		return 0, false
+	}
+	day := t.UTC().Format(dayFormat)
+	days := make([]string, 0, len(s.index.Days))
+	for d := range s.index.Days {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+	i := sort.SearchStrings(days, day)
+	if i == len(days) {
+		return s.index.Size, true
+	}
+	return s.index.Days[days[i]], true
+}
+
+func (s *Store) indexPlay(play Play, length int64) {
+	day := play.PlayedAt.UTC().Format(dayFormat)
+	if _, ok := s.index.Days[day]; !ok {
+		s.index.Days[day] = s.index.Size
+	}
+	s.index.Size += length
+	s.index.Count++
+	if play.PlayedAt.After(s.index.Latest) {
+		s.index.Latest = play.PlayedAt
+	}
+}
+
+func (s *Store) rebuild() error {
+	s.index = Index{
+		Days: map[string]int64{},
+	}
+	file, err := os.Open(filepath.Join(s.dir, playsFile))
+	if os.IsNotExist(err) {
+		return s.saveIndex()
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A trailing partial line is left by a write that was cut off, drop it
+			if len(line) > 0 {
+				file.Close()
+				if err := os.Truncate(filepath.Join(s.dir, playsFile), s.index.Size); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var play Play
+		if err := json.Unmarshal(line, &play); err != nil {
+			return errors.WithMessagef(err, "Failed to unmarshal play at offset %d", s.index.Size)
+		}
+		s.indexPlay(play, int64(len(line)))
+	}
+	return s.saveIndex()
+}
+
+func (s *Store) saveIndex() error {
+	data, err := json.Marshal(s.index)
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal history index")
+	}
+	tmp := filepath.Join(s.dir, indexFile+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.WithMessage(err, "Failed to write history index")
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, indexFile))
+}
+
+func (s *Store) playsSize() (int64, error) {
+	info, err := os.Stat(filepath.Join(s.dir, playsFile))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.WithMessage(err, "Failed to stat history")
+	}
+	return info.Size(), nil
+}
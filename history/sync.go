@@ -0,0 +1,70 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+// Sync pulls the plays newer than the latest one in the store and appends
+// them, returning how many were added. Spotify only keeps the last 50 plays,
+// so anything older than that at the time of syncing is lost for good.
+func Sync(ctx context.Context, client spotify.Client, store *Store) (int, error) {
+	added := 0
+	for {
+		out, err := client.GetRecentlyPlayed(ctx, store.Latest())
+		if err != nil {
+			return added, errors.WithMessage(err, "Failed to get recently played")
+		}
+
+		plays := make([]Play, 0, len(out.Items))
+		for _, item := range out.Items {
+			plays = append(plays, newPlay(item))
+		}
+		n, err := store.Append(plays)
+		added += n
+		if err != nil {
+			return added, err
+		}
+		if n == 0 || len(out.Items) < out.Limit {
+			return added, nil
+		}
+	}
+}
+
+// Daemon syncs every interval until ctx is done, reporting each sync to onSync
+func Daemon(ctx context.Context, client spotify.Client, store *Store, interval time.Duration, onSync func(added int, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		onSync(Sync(ctx, client, store))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func newPlay(item spotify.PlayHistory) Play {
+	play := Play{
+		PlayedAt:   item.PlayedAt,
+		TrackID:    item.Track.ID,
+		TrackName:  item.Track.Name,
+		TrackURI:   item.Track.URI,
+		DurationMS: item.Track.DurationMS,
+		AlbumID:    item.Track.Album.ID,
+		AlbumName:  item.Track.Album.Name,
+	}
+	for _, artist := range item.Track.Artists {
+		play.Artists = append(play.Artists, Artist{ID: artist.ID, Name: artist.Name})
+	}
+	if item.Context != nil {
+		play.ContextType = item.Context.Type
+		play.ContextURI = item.Context.URI
+	}
+	return play
+}
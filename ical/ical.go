@@ -0,0 +1,95 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// maxLine is the longest a content line may be in octets before it's folded
+const maxLine = 75
+
+// Calendar is a set of all-day events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Event is an all-day event from Start up to but not including End. UID must
+// stay the same across exports so subscribed calendars update the event
+// instead of adding it again.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+}
+
+// Write writes the calendar as iCalendar
+func Write(w io.Writer, c *Calendar) error {
+	out := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	line := func(name, value string) {
+		writeFolded(out, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//spotify-cli//releases//EN")
+	line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("DTSTAMP", stamp)
+		line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+		line("DTEND;VALUE=DATE", event.End.Format("20060102"))
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	if err := out.Flush(); err != nil {
+		return errors.WithMessage(err, "Failed to write calendar")
+	}
+	return nil
+}
+
+// escape escapes text values, which can't contain bare commas, semicolons or newlines
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeFolded writes a content line ending in CRLF, continuing it on lines
+// starting with a space whenever it gets too long, without splitting characters
+func writeFolded(out *bufio.Writer, content string) {
+	limit := maxLine
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		out.WriteString(content[:cut])
+		out.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space counts towards the continuation line
+		limit = maxLine - 1
+	}
+	out.WriteString(content)
+	out.WriteString("\r\n")
+}
@@ -0,0 +1,108 @@
+package local
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// vorbisFields maps Vorbis comment names to the tags they hold
+var vorbisFields = map[string]string{
+	"TITLE":       "title",
+	"ARTIST":      "artist",
+	"ALBUMARTIST": "albumartist",
+	"ALBUM":       "album",
+	"ISRC":        "isrc",
+}
+
+func readFLAC(file *os.File) (*Tags, error) {
+	// Some taggers put an ID3v2 tag in front of the stream
+	_, offset, err := readID3v2(file)
+	if err != nil {
+		return nil, err
+	}
+	marker := make([]byte, 4)
+	if _, err := file.ReadAt(marker, offset); err != nil || string(marker) != "fLaC" {
+		return nil, errors.New("Not a FLAC file")
+	}
+	offset += 4
+
+	tags := &Tags{}
+	header := make([]byte, 4)
+	for {
+		if _, err := file.ReadAt(header, offset); err != nil {
+			return nil, errors.WithMessage(err, "Failed to read metadata block")
+		}
+		last, blockType := header[0]&0x80 != 0, header[0]&0x7F
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+
+		switch blockType {
+		case 0:
+			block := make([]byte, length)
+			if _, err := file.ReadAt(block, offset); err != nil || length < 18 {
+				return nil, errors.New("Failed to read stream info")
+			}
+			sampleRate := int64(block[10])<<12 | int64(block[11])<<4 | int64(block[12])>>4
+			samples := int64(block[13]&0x0F)<<32 | int64(binary.BigEndian.Uint32(block[14:18]))
+			if sampleRate > 0 {
+				tags.Duration = time.Duration(samples * int64(time.Second) / sampleRate)
+			}
+		case 4:
+			block := make([]byte, length)
+			if _, err := file.ReadAt(block, offset); err != nil && err != io.EOF {
+				return nil, errors.WithMessage(err, "Failed to read comments")
+			}
+			comments := tagsFromFields(readVorbisComments(block))
+			comments.Duration = tags.Duration
+			tags = comments
+		}
+		offset += length
+		if last {
+			return tags, nil
+		}
+	}
+}
+
+// readVorbisComments reads the comment list used by FLAC, Vorbis and Opus
+// into fields by the names in vorbisFields, keeping the first of each
+func readVorbisComments(data []byte) map[string]string {
+	fields := map[string]string{}
+	next := func() (string, bool) {
+		if len(data) < 4 {
+			return "", false
+		}
+		length := binary.LittleEndian.Uint32(data)
+		if uint64(length) > uint64(len(data)-4) {
+			return "", false
+		}
+		s := string(data[4 : 4+length])
+		data = data[4+length:]
+		return s, true
+	}
+
+	// The vendor string comes first
+	if _, ok := next(); !ok || len(data) < 4 {
+		return fields
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			break
+		}
+		parts := strings.SplitN(comment, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if name, ok := vorbisFields[strings.ToUpper(parts[0])]; ok && fields[name] == "" {
+			fields[name] = parts[1]
+		}
+	}
+	return fields
+}
@@ -0,0 +1,92 @@
+package local
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func flacBlock(blockType byte, last bool, data []byte) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	n := len(data)
+	return concat([]byte{blockType, byte(n >> 16), byte(n >> 8), byte(n)}, data)
+}
+
+func streamInfo(sampleRate int, samples int64) []byte {
+	b := make([]byte, 34)
+	b[10] = byte(sampleRate >> 12)
+	b[11] = byte(sampleRate >> 4)
+	// Two channels and 16 bits per sample share these bytes with the rest
+	b[12] = byte(sampleRate<<4) | 1<<1
+	b[13] = 0xF0 | byte(samples>>32)&0x0F
+	binary.BigEndian.PutUint32(b[14:], uint32(samples))
+	return b
+}
+
+func vorbisComments(comments ...string) []byte {
+	vendor := "reference libFLAC 1.4.3"
+	data := concat(le32(uint32(len(vendor))), []byte(vendor), le32(uint32(len(comments))))
+	for _, comment := range comments {
+		data = concat(data, le32(uint32(len(comment))), []byte(comment))
+	}
+	return data
+}
+
+func flacTests() []tagsTest {
+	return []tagsTest{
+		{
+			name: "stream info, padding and comments",
+			file: "a.flac",
+			data: concat([]byte("fLaC"),
+				flacBlock(0, false, streamInfo(44100, 44100*200)),
+				flacBlock(1, false, make([]byte, 10)),
+				flacBlock(4, true, vorbisComments(
+					"title=Flac Song",
+					"ARTIST=Flac Artist",
+					"Album=Flac Album",
+					"no equals sign",
+					"ARTIST=Second Artist",
+					"ISRC=GB-AYE-06-01498",
+				)),
+			),
+			want: Tags{Title: "Flac Song", Artist: "Flac Artist", Album: "Flac Album", ISRC: "GBAYE0601498", Duration: 200 * time.Second},
+		},
+		{
+			name: "ID3v2 in front and album artist",
+			file: "b.flac",
+			data: concat(id3Tag(3, 0, frame23("TIT2", 0, latin1("Ignored"))), []byte("fLaC"),
+				flacBlock(0, false, streamInfo(48000, 48000*90+24000)),
+				flacBlock(4, true, vorbisComments("TITLE=Tagged", "ALBUMARTIST=Album Artist")),
+			),
+			want: Tags{Title: "Tagged", Artist: "Album Artist", Duration: seconds(90.5)},
+		},
+		{
+			name: "more samples than fit in 32 bits",
+			file: "Long - Recording.flac",
+			data: concat([]byte("fLaC"), flacBlock(0, true, streamInfo(96000, 96000*60*60*13))),
+			want: Tags{Title: "Recording", Artist: "Long", Duration: 13 * time.Hour},
+		},
+		{
+			name: "comment count larger than the block",
+			file: "c.flac",
+			data: concat([]byte("fLaC"),
+				flacBlock(0, false, streamInfo(44100, 44100)),
+				flacBlock(4, true, concat(le32(0), le32(1000), le32(7), []byte("TITLE=T"), le32(99), []byte("ARTIST="))),
+			),
+			want: Tags{Title: "T", Duration: time.Second},
+		},
+	}
+}
+
+func TestReadFLAC(t *testing.T) {
+	runTagsTests(t, flacTests())
+	runErrorTests(t, []errorTest{
+		{"not FLAC", "a.flac", []byte("OggS not really")},
+		{"empty", "b.flac", nil},
+		{"truncated stream info", "c.flac", concat([]byte("fLaC"), []byte{0x80, 0, 0, 34}, make([]byte, 10))},
+		{"short stream info", "d.flac", concat([]byte("fLaC"), flacBlock(0, true, make([]byte, 10)))},
+		{"no last block", "e.flac", concat([]byte("fLaC"), flacBlock(0, false, streamInfo(44100, 44100)))},
+	})
+}
@@ -0,0 +1,265 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// id3Frames maps the ID3v2.3 and v2.4 frame IDs, and the shorter v2.2 ones,
+// to the tags they hold
+var id3Frames = map[string]string{
+	"TIT2": "title", "TT2": "title",
+	"TPE1": "artist", "TP1": "artist",
+	"TPE2": "albumartist", "TP2": "albumartist",
+	"TALB": "album", "TAL": "album",
+	"TSRC": "isrc", "TRC": "isrc",
+	"TLEN": "length", "TLE": "length",
+}
+
+// readID3v2 reads the ID3v2 tag at the start of r, if there is one, into
+// fields by the names in id3Frames. It returns the size of the tag so the
+// audio after it can be found.
+func readID3v2(r io.ReaderAt) (map[string]string, int64, error) {
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil || string(header[:3]) != "ID3" {
+		return nil, 0, nil
+	}
+	version, flags := header[3], header[5]
+	size := int64(syncsafe(header[6:10]))
+	tagSize := 10 + size
+	if version == 4 && flags&0x10 != 0 {
+		tagSize += 10
+	}
+	if version < 2 || version > 4 {
+		return nil, tagSize, nil
+	}
+
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 10); err != nil && err != io.EOF {
+		return nil, 0, errors.WithMessage(err, "Failed to read ID3 tag")
+	}
+	// Before v2.4 unsynchronisation applies to the whole tag, from v2.4 on to each frame
+	if flags&0x80 != 0 && version < 4 {
+		data = resync(data)
+	}
+	if flags&0x40 != 0 {
+		switch version {
+		case 2:
+			// v2.2 used this flag for compression, which was never specified
+			return nil, tagSize, nil
+		case 3:
+			if len(data) < 4 {
+				return nil, tagSize, nil
+			}
+			data = skip(data, 4+int(binary.BigEndian.Uint32(data)))
+		case 4:
+			if len(data) < 4 {
+				return nil, tagSize, nil
+			}
+			data = skip(data, int(syncsafe(data)))
+		}
+	}
+
+	fields := map[string]string{}
+	for {
+		var id string
+		var frameSize, headerSize int
+		var frameFlags byte
+		if version == 2 {
+			if len(data) < 6 {
+				break
+			}
+			id = string(data[:3])
+			frameSize = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+			headerSize = 6
+		} else {
+			if len(data) < 10 {
+				break
+			}
+			id = string(data[:4])
+			if version == 4 {
+				frameSize = int(syncsafe(data[4:8]))
+			} else {
+				frameSize = int(binary.BigEndian.Uint32(data[4:8]))
+			}
+			frameFlags = data[9]
+			headerSize = 10
+		}
+		// Padding, or garbage, ends the frames
+		if id[0] < 'A' || id[0] > 'Z' || frameSize < 0 || headerSize+frameSize > len(data) {
+			break
+		}
+		frame := data[headerSize : headerSize+frameSize]
+		data = data[headerSize+frameSize:]
+
+		name, ok := id3Frames[id]
+		if !ok || fields[name] != "" {
+			continue
+		}
+		frame, ok = frameContent(frame, version, frameFlags)
+		if !ok {
+			continue
+		}
+		fields[name] = decodeID3Text(frame)
+	}
+	return fields, tagSize, nil
+}
+
+// frameContent strips what the frame flags add before a frame's content. It
+// reports false for compressed and encrypted frames.
+func frameContent(frame []byte, version, flags byte) ([]byte, bool) {
+	switch version {
+	case 3:
+		if flags&0xC0 != 0 {
+			return nil, false
+		}
+		if flags&0x20 != 0 {
+			frame = skip(frame, 1)
+		}
+	case 4:
+		if flags&0x0C != 0 {
+			return nil, false
+		}
+		if flags&0x40 != 0 {
+			frame = skip(frame, 1)
+		}
+		if flags&0x01 != 0 {
+			frame = skip(frame, 4)
+		}
+		if flags&0x02 != 0 {
+			frame = resync(frame)
+		}
+	}
+	return frame, true
+}
+
+// decodeID3Text decodes a text frame in any of its four encodings, keeping
+// the first value when there are several
+func decodeID3Text(frame []byte) string {
+	if len(frame) == 0 {
+		return ""
+	}
+	encoding, text := frame[0], frame[1:]
+	var s string
+	switch encoding {
+	case 0:
+		runes := make([]rune, len(text))
+		for i, b := range text {
+			runes[i] = rune(b)
+		}
+		s = string(runes)
+	case 1, 2:
+		bigEndian := encoding == 2
+		if len(text) >= 2 && text[0] == 0xFE && text[1] == 0xFF {
+			bigEndian, text = true, text[2:]
+		} else if len(text) >= 2 && text[0] == 0xFF && text[1] == 0xFE {
+			bigEndian, text = false, text[2:]
+		}
+		units := make([]uint16, len(text)/2)
+		for i := range units {
+			if bigEndian {
+				units[i] = binary.BigEndian.Uint16(text[2*i:])
+			} else {
+				units[i] = binary.LittleEndian.Uint16(text[2*i:])
+			}
+		}
+		s = string(utf16.Decode(units))
+	default:
+		s = string(text)
+	}
+	if i := strings.IndexRune(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// readID3v1 reads the fixed size tag some MP3s have in their last 128 bytes
+func readID3v1(file *os.File, size int64) map[string]string {
+	if size < 128 {
+		return nil
+	}
+	tag := make([]byte, 128)
+	if _, err := file.ReadAt(tag, size-128); err != nil || string(tag[:3]) != "TAG" {
+		return nil
+	}
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return decodeID3Text(append([]byte{0}, b...))
+	}
+	return map[string]string{
+		"title":  field(tag[3:33]),
+		"artist": field(tag[33:63]),
+		"album":  field(tag[63:93]),
+	}
+}
+
+func readMP3(file *os.File) (*Tags, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fields, tagSize, err := readID3v2(file)
+	if err != nil {
+		return nil, err
+	}
+	end := info.Size()
+	if v1 := readID3v1(file, info.Size()); v1 != nil {
+		end -= 128
+		if fields == nil {
+			fields = v1
+		}
+	}
+	if fields == nil {
+		fields = map[string]string{}
+	}
+
+	tags := tagsFromFields(fields)
+	if ms, err := strconv.Atoi(strings.TrimSpace(fields["length"])); err == nil && ms > 0 {
+		tags.Duration = time.Duration(ms) * time.Millisecond
+	} else {
+		tags.Duration = mp3Duration(file, tagSize, end)
+	}
+	return tags, nil
+}
+
+// tagsFromFields picks the tags out of the fields read from ID3 or Vorbis
+// comments, using the album artist when there's no track artist
+func tagsFromFields(fields map[string]string) *Tags {
+	tags := &Tags{
+		Title:  fields["title"],
+		Artist: fields["artist"],
+		Album:  fields["album"],
+		ISRC:   fields["isrc"],
+	}
+	if tags.Artist == "" {
+		tags.Artist = fields["albumartist"]
+	}
+	return tags
+}
+
+// syncsafe decodes the 28 bit integers ID3v2 stores in 4 bytes of 7 bits each
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7F)<<21 | uint32(b[1]&0x7F)<<14 | uint32(b[2]&0x7F)<<7 | uint32(b[3]&0x7F)
+}
+
+// resync undoes unsynchronisation, which puts a zero byte after every 0xFF
+func resync(data []byte) []byte {
+	return bytes.Replace(data, []byte{0xFF, 0x00}, []byte{0xFF}, -1)
+}
+
+func skip(data []byte, n int) []byte {
+	if n > len(data) {
+		return nil
+	}
+	return data[n:]
+}
@@ -0,0 +1,224 @@
+package local
+
+import (
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+func id3Tag(version, flags byte, body ...[]byte) []byte {
+	data := concat(body...)
+	return concat([]byte("ID3"), []byte{version, 0, flags}, syncsafeBytes(len(data)), data)
+}
+
+func frame22(id string, content []byte) []byte {
+	n := len(content)
+	return concat([]byte(id), []byte{byte(n >> 16), byte(n >> 8), byte(n)}, content)
+}
+
+func frame23(id string, flags byte, content []byte) []byte {
+	return concat([]byte(id), be32(uint32(len(content))), []byte{0, flags}, content)
+}
+
+func frame24(id string, flags byte, content []byte) []byte {
+	return concat([]byte(id), syncsafeBytes(len(content)), []byte{0, flags}, content)
+}
+
+func latin1(s string) []byte {
+	b := []byte{0}
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return b
+}
+
+func utf16BOM(s string) []byte {
+	b := []byte{1, 0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u), byte(u>>8))
+	}
+	return append(b, 0, 0)
+}
+
+func utf16BE(s string) []byte {
+	b := []byte{2}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u>>8), byte(u))
+	}
+	return b
+}
+
+func utf8Text(s string) []byte {
+	return append([]byte{3}, s...)
+}
+
+// unsynchronise puts a zero byte after every 0xFF that comes before a byte
+// that could be mistaken for a frame sync, or a zero, or the end
+func unsynchronise(data []byte) []byte {
+	var out []byte
+	for i, b := range data {
+		out = append(out, b)
+		if b == 0xFF && (i+1 == len(data) || data[i+1] == 0 || data[i+1] >= 0xE0) {
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+// cbrAudio is a second of 128 kbit/s MPEG-1 layer III audio
+func cbrAudio() []byte {
+	audio := make([]byte, 16000)
+	copy(audio, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return audio
+}
+
+// xingAudio starts with a Xing header saying the file has 1000 frames
+func xingAudio() []byte {
+	audio := make([]byte, 4000)
+	copy(audio, []byte{0xFF, 0xFB, 0x90, 0x00})
+	copy(audio[36:], concat([]byte("Xing"), be32(1), be32(1000)))
+	return audio
+}
+
+func id3v1(title, artist, album string) []byte {
+	field := func(s string) []byte {
+		b := make([]byte, 30)
+		copy(b, s)
+		return b
+	}
+	return concat([]byte("TAG"), field(title), field(artist), field(album), make([]byte, 35))
+}
+
+func id3Tests() []tagsTest {
+	return []tagsTest{
+		{
+			name: "v2.2",
+			file: "a.mp3",
+			data: concat(id3Tag(2, 0,
+				frame22("TT2", latin1("Teardrop")),
+				frame22("TP1", latin1("Massive Attack")),
+				frame22("TAL", latin1("Mezzanine")),
+				frame22("TRC", latin1("gb-aaa-98-00001")),
+			), cbrAudio()),
+			want: Tags{Title: "Teardrop", Artist: "Massive Attack", Album: "Mezzanine", ISRC: "GBAAA9800001", Duration: time.Second},
+		},
+		{
+			name: "v2.3 UTF-16 with album artist, length and padding",
+			file: "b.mp3",
+			data: concat(id3Tag(3, 0,
+				frame23("TIT2", 0, utf16BOM("Héllo Wörld")),
+				frame23("TPE2", 0, latin1("Beyoncé")),
+				frame23("TLEN", 0, latin1("215000")),
+				make([]byte, 100),
+			), cbrAudio()),
+			want: Tags{Title: "Héllo Wörld", Artist: "Beyoncé", Duration: 215 * time.Second},
+		},
+		{
+			name: "v2.3 unsynchronisation",
+			file: "c.mp3",
+			data: concat(id3Tag(3, 0x80, unsynchronise(concat(
+				frame23("TIT2", 0, latin1("ÿé")),
+				frame23("TPE1", 0, latin1("Artist")),
+				frame23("TALB", 0, []byte{0, 0xFF}),
+			))), cbrAudio()),
+			want: Tags{Title: "ÿé", Artist: "Artist", Album: "ÿ", Duration: time.Second},
+		},
+		{
+			name: "v2.3 extended header",
+			file: "d.mp3",
+			data: concat(id3Tag(3, 0x40,
+				be32(6), make([]byte, 6),
+				frame23("TIT2", 0, latin1("Extended")),
+				frame23("TPE1", 0, latin1("Header")),
+			), cbrAudio()),
+			want: Tags{Title: "Extended", Artist: "Header", Duration: time.Second},
+		},
+		{
+			name: "v2.4 extended header and frame flags",
+			file: "e.mp3",
+			data: concat(id3Tag(4, 0x40,
+				syncsafeBytes(6), []byte{1, 0},
+				// Unsynchronised, with a data length indicator
+				frame24("TIT2", 0x03, concat(syncsafeBytes(11), unsynchronise(latin1("ÿé Svefn-g")))),
+				// Grouped
+				frame24("TPE1", 0x40, concat([]byte{7}, utf8Text("Sigur Rós"))),
+				frame24("TALB", 0, utf16BE("Ágætis byrjun")),
+				// Compressed frames are skipped
+				frame24("TSRC", 0x08, []byte{0x78, 0x9C, 0x01}),
+				frame24("TSRC", 0, utf8Text("isxx10000001")),
+				make([]byte, 20),
+			), cbrAudio()),
+			want: Tags{Title: "ÿé Svefn-g", Artist: "Sigur Rós", Album: "Ágætis byrjun", ISRC: "ISXX10000001", Duration: time.Second},
+		},
+		{
+			name: "duplicate frames keep the first",
+			file: "f.mp3",
+			data: concat(id3Tag(3, 0,
+				frame23("TIT2", 0, latin1("First")),
+				frame23("TIT2", 0, latin1("Second")),
+				frame23("TPE1", 0, latin1("Artist\x00Other Artist")),
+			), cbrAudio()),
+			want: Tags{Title: "First", Artist: "Artist", Duration: time.Second},
+		},
+		{
+			name: "ID3v1",
+			file: "g.mp3",
+			data: concat(cbrAudio(), id3v1("Old Title", "Old Artist", "Old Album")),
+			want: Tags{Title: "Old Title", Artist: "Old Artist", Album: "Old Album", Duration: time.Second},
+		},
+		{
+			name: "ID3v2 over ID3v1",
+			file: "h.mp3",
+			data: concat(id3Tag(3, 0, frame23("TIT2", 0, latin1("New Title"))), cbrAudio(), id3v1("Old Title", "Old Artist", "")),
+			want: Tags{Title: "New Title", Duration: time.Second},
+		},
+		{
+			name: "Xing header and file name",
+			file: "Daft Punk - One More Time.mp3",
+			data: xingAudio(),
+			want: Tags{Title: "One More Time", Artist: "Daft Punk", Duration: time.Duration(1000 * 1152 * int64(time.Second) / 44100)},
+		},
+		{
+			name: "artist tag and title from file name",
+			file: "07. Title Only.mp3",
+			data: concat(id3Tag(3, 0, frame23("TPE1", 0, latin1("Tagged Artist"))), cbrAudio()),
+			want: Tags{Title: "Title Only", Artist: "Tagged Artist", Duration: time.Second},
+		},
+		{
+			name: "unknown version",
+			file: "Unknown - Version.mp3",
+			data: concat(id3Tag(5, 0, frame23("TIT2", 0, latin1("Ignored"))), cbrAudio()),
+			want: Tags{Title: "Version", Artist: "Unknown", Duration: time.Second},
+		},
+	}
+}
+
+func TestReadMP3(t *testing.T) {
+	runTagsTests(t, id3Tests())
+}
+
+func TestDecodeID3Text(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		want  string
+	}{
+		{"empty", nil, ""},
+		{"latin-1", latin1("Café"), "Café"},
+		{"UTF-16 little endian", utf16BOM("Œuvre 🎵"), "Œuvre 🎵"},
+		{"UTF-16 big endian BOM", []byte{1, 0xFE, 0xFF, 0, 'O', 0, 'K'}, "OK"},
+		{"UTF-16 odd length", []byte{1, 0xFF, 0xFE, 'O', 0, 'K'}, "O"},
+		{"UTF-16BE", utf16BE("Ágætis"), "Ágætis"},
+		{"UTF-8", utf8Text("Sigur Rós"), "Sigur Rós"},
+		{"first of several values", utf8Text("One\x00Two"), "One"},
+	}
+	for _, test := range tests {
+		if got := decodeID3Text(test.frame); got != test.want {
+			t.Errorf("%s: decodeID3Text = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
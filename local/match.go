@@ -0,0 +1,255 @@
+package local
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cwseger/spotify-cli/fuzzy"
+	"github.com/cwseger/spotify-cli/parallel"
+	"github.com/cwseger/spotify-cli/spotify"
+	"github.com/pkg/errors"
+)
+
+// Methods a track can be found by
+const (
+	MethodISRC   = "isrc"
+	MethodSearch = "search"
+)
+
+// searchLimit is how many search results are scored for each file
+const searchLimit = 10
+
+// Match is the Spotify track found for a local file
+type Match struct {
+	Path string `json:"path"`
+	Tags *Tags  `json:"tags,omitempty"`
+	// Track is the best candidate, however low its confidence, or nil if
+	// nothing came up at all
+	Track *spotify.Track `json:"track,omitempty"`
+	// Confidence goes from 0 for no resemblance to 1 for a sure match
+	Confidence float64 `json:"confidence"`
+	Method     string  `json:"method,omitempty"`
+	// Error is why the file's tags couldn't be read
+	Error string `json:"error,omitempty"`
+}
+
+// Options -
+type Options struct {
+	// Concurrency is how many files are matched at once
+	Concurrency int
+}
+
+// MatchFiles reads the tags of every file and finds the Spotify track that
+// fits them best, returning the matches in the same order as paths. Files
+// that can't be read get a match with an Error rather than failing the rest.
+func MatchFiles(ctx context.Context, client spotify.Client, paths []string, options Options) ([]Match, error) {
+	matches := make([]Match, len(paths))
+	if err := parallel.ForEach(ctx, len(paths), options.Concurrency, func(ctx context.Context, i int) error {
+		matches[i].Path = paths[i]
+		tags, err := Read(paths[i])
+		if err != nil {
+			matches[i].Error = err.Error()
+			return nil
+		}
+		matches[i].Tags = tags
+		track, confidence, method, err := matchTrack(ctx, client, tags)
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to match %s", paths[i])
+		}
+		matches[i].Track, matches[i].Confidence, matches[i].Method = track, confidence, method
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// matchTrack looks the file up by ISRC when it has one, which is all but
+// certain, and otherwise searches by title and artist and scores the results
+func matchTrack(ctx context.Context, client spotify.Client, tags *Tags) (*spotify.Track, float64, string, error) {
+	if tags.ISRC != "" {
+		tracks, err := client.SearchTracksByISRC(ctx, tags.ISRC)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		// The same recording can be on several albums, so the tags still
+		// pick between them
+		if track, score := best(tags, tracks); track != nil {
+			return track, 0.9 + 0.1*score, MethodISRC, nil
+		}
+	}
+	if tags.Title == "" {
+		return nil, 0, "", nil
+	}
+
+	var tracks []spotify.Track
+	for _, query := range searchQueries(tags) {
+		found, err := client.SearchTracks(ctx, query, searchLimit)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		tracks = append(tracks, found...)
+		if len(found) > 0 {
+			break
+		}
+	}
+	track, score := best(tags, tracks)
+	if track == nil {
+		return nil, 0, "", nil
+	}
+	return track, score, MethodSearch, nil
+}
+
+// searchQueries are tried in turn until one finds something: field filters
+// first, then the words of the title and artist on their own
+func searchQueries(tags *Tags) []string {
+	clean := func(s string) string {
+		return strings.TrimSpace(strings.Replace(s, `"`, "", -1))
+	}
+	title := clean(tags.Title)
+	var queries []string
+	if tags.Artist != "" {
+		queries = append(queries, `track:"`+title+`" artist:"`+clean(tags.Artist)+`"`)
+	} else {
+		queries = append(queries, `track:"`+title+`"`)
+	}
+	return append(queries, strings.TrimSpace(stripExtras(title)+" "+clean(tags.Artist)))
+}
+
+func best(tags *Tags, tracks []spotify.Track) (*spotify.Track, float64) {
+	var bestTrack *spotify.Track
+	bestScore := -1.0
+	for i := range tracks {
+		if score := Score(tags, tracks[i]); score > bestScore {
+			bestTrack, bestScore = &tracks[i], score
+		}
+	}
+	return bestTrack, bestScore
+}
+
+// Score rates how well a track fits a file's tags from 0 to 1, by title,
+// artist, album and duration. What the file doesn't have tags for isn't
+// counted against the track.
+func Score(tags *Tags, track spotify.Track) float64 {
+	var total, weights float64
+	add := func(weight, score float64) {
+		total += weight * score
+		weights += weight
+	}
+
+	add(0.45, titleSimilarity(tags.Title, track.Name))
+	if tags.Artist != "" {
+		add(0.3, artistSimilarity(tags.Artist, track.Artists))
+	}
+	if tags.Album != "" {
+		add(0.1, titleSimilarity(tags.Album, track.Album.Name))
+	}
+	if tags.Duration > 0 && track.DurationMS > 0 {
+		add(0.15, durationSimilarity(tags.Duration, time.Duration(track.DurationMS)*time.Millisecond))
+	}
+	return total / weights
+}
+
+// extras are the version notes added to titles, such as "(Live)",
+// "[2011 Remaster]" or " - Remastered 2009"
+var extras = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\]|\s-\s.*$)`)
+
+func stripExtras(title string) string {
+	if stripped := strings.TrimSpace(extras.ReplaceAllString(title, "")); stripped != "" {
+		return stripped
+	}
+	return title
+}
+
+// titleSimilarity compares titles as they are and without version notes,
+// since either side can have them
+func titleSimilarity(a, b string) float64 {
+	score := similarity(a, b)
+	if stripped := similarity(stripExtras(a), stripExtras(b)); stripped > score {
+		score = stripped
+	}
+	return score
+}
+
+// artistSimilarity compares the tagged artist with the track's main artist
+// and with all its artists together, for tags like "A & B" or "A feat. B"
+func artistSimilarity(artist string, artists []spotify.Artist) float64 {
+	if len(artists) == 0 {
+		return 0
+	}
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	score := similarity(artist, names[0])
+	if all := similarity(artist, strings.Join(names, " ")); all > score {
+		score = all
+	}
+	return score
+}
+
+// durationSimilarity is 1 for lengths within a couple of seconds of each
+// other, falling to 0 at 20 seconds apart
+func durationSimilarity(a, b time.Duration) float64 {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= 2*time.Second:
+		return 1
+	case diff >= 20*time.Second:
+		return 0
+	}
+	return 1 - float64(diff-2*time.Second)/float64(18*time.Second)
+}
+
+// similarity compares two names from 0 to 1, as the better of how few edits
+// turn one into the other and how many words they share
+func similarity(a, b string) float64 {
+	a, b = normalize(a), normalize(b)
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+	longest := len([]rune(a))
+	if n := len([]rune(b)); n > longest {
+		longest = n
+	}
+	score := 1 - float64(fuzzy.EditDistance(a, b))/float64(longest)
+	if words := wordOverlap(a, b); words > score {
+		score = words
+	}
+	return score
+}
+
+// normalize lowercases a name and reduces it to words of letters and digits
+func normalize(s string) string {
+	s = strings.Replace(strings.ToLower(s), "&", " and ", -1)
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// wordOverlap is the share of the words in both names out of the words in either
+func wordOverlap(a, b string) float64 {
+	words := map[string]int{}
+	for _, word := range strings.Fields(a) {
+		words[word] |= 1
+	}
+	for _, word := range strings.Fields(b) {
+		words[word] |= 2
+	}
+	both := 0
+	for _, in := range words {
+		if in == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(words))
+}
@@ -0,0 +1,121 @@
+package local
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"One More Time", "one more time", 1},
+		{"Hip-Hop", "hip hop", 1},
+		{"Simon & Garfunkel", "Simon and Garfunkel", 1},
+		{"", "", 1},
+		{"", "Something", 0},
+		{"abc", "xyz", 0},
+		// Shared words beat the edit distance of reordered names
+		{"Time More One", "One More Time", 1},
+		{"Beyoncé", "Beyonce", 1 - 1.0/7},
+		{"Harder Better Faster Stronger", "Harder Better", 0.5},
+	}
+	for _, test := range tests {
+		if got := similarity(test.a, test.b); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+		if got := similarity(test.b, test.a); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, want %v", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestTitleSimilarityIgnoresVersionNotes(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"Heroes", "Heroes - 2017 Remaster"},
+		{"Heroes (Live)", "Heroes"},
+		{"Heroes [Single Version]", "Heroes"},
+	}
+	for _, test := range tests {
+		if got := titleSimilarity(test.a, test.b); got != 1 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want 1", test.a, test.b, got)
+		}
+	}
+}
+
+func TestDurationSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b time.Duration
+		want float64
+	}{
+		{200 * time.Second, 200 * time.Second, 1},
+		{200 * time.Second, 202 * time.Second, 1},
+		{211 * time.Second, 200 * time.Second, 0.5},
+		{200 * time.Second, 220 * time.Second, 0},
+		{200 * time.Second, 300 * time.Second, 0},
+	}
+	for _, test := range tests {
+		if got := durationSimilarity(test.a, test.b); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("durationSimilarity(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func track(name string, artists []string, album string, duration time.Duration) spotify.Track {
+	t := spotify.Track{Name: name, DurationMS: int(duration / time.Millisecond)}
+	for _, artist := range artists {
+		t.Artists = append(t.Artists, spotify.Artist{Name: artist})
+	}
+	t.Album.Name = album
+	return t
+}
+
+func TestScore(t *testing.T) {
+	tags := &Tags{Title: "One More Time", Artist: "Daft Punk", Album: "Discovery", Duration: 320 * time.Second}
+	tests := []struct {
+		name  string
+		tags  *Tags
+		track spotify.Track
+		want  float64
+	}{
+		{"exact", tags, track("One More Time", []string{"Daft Punk"}, "Discovery", 320*time.Second), 1},
+		{"remastered", tags, track("One More Time - Remastered", []string{"Daft Punk"}, "Discovery (Remastered)", 321*time.Second), 1},
+		{"different album", tags, track("One More Time", []string{"Daft Punk"}, "Alive 2007", 320*time.Second), 0.9 + 0.1*similarity("Discovery", "Alive 2007")},
+		{"wrong duration", tags, track("One More Time", []string{"Daft Punk"}, "Discovery", 400*time.Second), 0.85},
+		{"wrong artist", tags, track("One More Time", []string{"Cover Band"}, "Discovery", 320*time.Second), 0.7 + 0.3*similarity("Daft Punk", "Cover Band")},
+		{
+			"missing tags aren't counted against",
+			&Tags{Title: "One More Time"},
+			track("One More Time", []string{"Daft Punk"}, "Discovery", 320*time.Second),
+			1,
+		},
+		{
+			"several artists",
+			&Tags{Title: "Get Lucky", Artist: "Daft Punk & Pharrell Williams"},
+			track("Get Lucky", []string{"Daft Punk", "Pharrell Williams", "Nile Rodgers"}, "Random Access Memories", 369*time.Second),
+			(0.45 + 0.3*similarity("Daft Punk & Pharrell Williams", "Daft Punk Pharrell Williams Nile Rodgers")) / 0.75,
+		},
+	}
+	for _, test := range tests {
+		if got := Score(test.tags, test.track); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: Score = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestScoreRanksTheRightTrackFirst(t *testing.T) {
+	tags := &Tags{Title: "Heroes", Artist: "David Bowie", Album: "Heroes", Duration: 371 * time.Second}
+	tracks := []spotify.Track{
+		track("Heroes", []string{"Peter Gabriel"}, "Scratch My Back", 423*time.Second),
+		track("Heroes - 2017 Remaster", []string{"David Bowie"}, "Heroes (2017 Remaster)", 371*time.Second),
+		track("Heroes (Single Version)", []string{"David Bowie"}, "Best of Bowie", 213*time.Second),
+	}
+	got, score := best(tags, tracks)
+	if got != &tracks[1] || score != 1 {
+		t.Errorf("Best track = %q by %s with %v, want the remaster with 1", got.Name, got.Artists[0].Name, score)
+	}
+}
@@ -0,0 +1,124 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"time"
+)
+
+// Bitrates in kbit/s by bitrate index, for MPEG-1 layers I to III and then
+// MPEG-2 and 2.5 layer I and layers II and III
+var mp3Bitrates = [5][16]int{
+	{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+}
+
+// Sample rates in Hz by sample rate index, for MPEG-1
+var mp3SampleRates = [3]int{44100, 48000, 32000}
+
+// mp3Frame is what's needed from an MPEG audio frame header to work out the
+// length of the file
+type mp3Frame struct {
+	mpeg1           bool
+	layer           int
+	bitrate         int
+	sampleRate      int
+	samplesPerFrame int
+	mono            bool
+}
+
+// mp3Duration works out how long the audio between start and end is from the
+// frame count in a Xing, Info or VBRI header, or from the bitrate of the first
+// frame for files without one, which are constant bitrate
+func mp3Duration(file *os.File, start, end int64) time.Duration {
+	buf := make([]byte, 64*1024)
+	n, _ := file.ReadAt(buf, start)
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xFF || buf[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+		frame, ok := parseMP3Frame(buf[i:])
+		if !ok {
+			continue
+		}
+		if frames := vbrFrames(buf[i:], frame); frames > 0 {
+			return time.Duration(int64(frames) * int64(frame.samplesPerFrame) * int64(time.Second) / int64(frame.sampleRate))
+		}
+		audioBytes := end - start - int64(i)
+		return time.Duration(audioBytes * 8 * int64(time.Second) / int64(frame.bitrate*1000))
+	}
+	return 0
+}
+
+func parseMP3Frame(header []byte) (mp3Frame, bool) {
+	version := (header[1] >> 3) & 0x03
+	layerBits := (header[1] >> 1) & 0x03
+	bitrateIndex := header[2] >> 4
+	sampleRateIndex := (header[2] >> 2) & 0x03
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return mp3Frame{}, false
+	}
+
+	frame := mp3Frame{
+		mpeg1:      version == 3,
+		layer:      4 - int(layerBits),
+		sampleRate: mp3SampleRates[sampleRateIndex],
+		mono:       header[3]>>6 == 3,
+	}
+	switch version {
+	case 2:
+		frame.sampleRate /= 2
+	case 0:
+		frame.sampleRate /= 4
+	}
+
+	switch {
+	case frame.mpeg1:
+		frame.bitrate = mp3Bitrates[frame.layer-1][bitrateIndex]
+	case frame.layer == 1:
+		frame.bitrate = mp3Bitrates[3][bitrateIndex]
+	default:
+		frame.bitrate = mp3Bitrates[4][bitrateIndex]
+	}
+
+	switch {
+	case frame.layer == 1:
+		frame.samplesPerFrame = 384
+	case frame.layer == 3 && !frame.mpeg1:
+		frame.samplesPerFrame = 576
+	default:
+		frame.samplesPerFrame = 1152
+	}
+	return frame, true
+}
+
+// vbrFrames reads the number of frames from the Xing or Info header that
+// follows the side information of the first frame, or from a VBRI header
+func vbrFrames(data []byte, frame mp3Frame) int {
+	offset := 4 + 17
+	switch {
+	case frame.mpeg1 && !frame.mono:
+		offset = 4 + 32
+	case !frame.mpeg1 && frame.mono:
+		offset = 4 + 9
+	}
+	if offset+12 <= len(data) {
+		if id := string(data[offset : offset+4]); id == "Xing" || id == "Info" {
+			flags := binary.BigEndian.Uint32(data[offset+4:])
+			if flags&0x01 != 0 {
+				return int(binary.BigEndian.Uint32(data[offset+8:]))
+			}
+			return 0
+		}
+	}
+	if 4+32+18 <= len(data) && bytes.Equal(data[4+32:4+36], []byte("VBRI")) {
+		return int(binary.BigEndian.Uint32(data[4+32+14:]))
+	}
+	return 0
+}
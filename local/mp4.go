@@ -0,0 +1,182 @@
+package local
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxMoov bounds the size of the movie box that's read into memory
+const maxMoov = 64 << 20
+
+// mp4Items maps the iTunes metadata items to the tags they hold
+var mp4Items = map[string]string{
+	"\xa9nam": "title",
+	"\xa9ART": "artist",
+	"aART":    "albumartist",
+	"\xa9alb": "album",
+}
+
+// mp4Box is a box, or atom, with its payload
+type mp4Box struct {
+	kind    string
+	payload []byte
+}
+
+func readMP4(file *os.File) (*Tags, error) {
+	moov, err := findMoov(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	var duration time.Duration
+	for _, box := range mp4Boxes(moov) {
+		switch box.kind {
+		case "mvhd":
+			duration = mvhdDuration(box.payload)
+		case "udta":
+			for _, meta := range mp4Boxes(box.payload) {
+				if meta.kind == "meta" {
+					readIlst(metaChildren(meta.payload), fields)
+				}
+			}
+		case "meta":
+			readIlst(metaChildren(box.payload), fields)
+		}
+	}
+	tags := tagsFromFields(fields)
+	tags.Duration = duration
+	return tags, nil
+}
+
+// findMoov reads the movie box, which holds the metadata, skipping over the
+// media data that can come before it
+func findMoov(r io.ReaderAt) ([]byte, error) {
+	header := make([]byte, 16)
+	var offset int64
+	for {
+		n, err := r.ReadAt(header, offset)
+		if n < 8 {
+			if offset == 0 {
+				return nil, errors.New("Not an MP4 file")
+			}
+			return nil, errors.New("No movie box found")
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+		kind := string(header[4:8])
+		if offset == 0 && kind != "ftyp" {
+			return nil, errors.New("Not an MP4 file")
+		}
+		switch size {
+		case 0:
+			// The box runs to the end of the file
+			size = 1 << 62
+		case 1:
+			if n < 16 {
+				return nil, errors.New("Truncated box header")
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(header[8:])), 16
+		}
+		if size < headerSize {
+			return nil, errors.New("Invalid box size")
+		}
+		if kind == "moov" {
+			if size > maxMoov {
+				return nil, errors.New("Movie box is too large")
+			}
+			moov := make([]byte, size-headerSize)
+			if _, err := r.ReadAt(moov, offset+headerSize); err != nil && err != io.EOF {
+				return nil, errors.WithMessage(err, "Failed to read movie box")
+			}
+			return moov, nil
+		}
+		offset += size
+	}
+}
+
+// mp4Boxes splits data into the boxes it holds, stopping at the first that doesn't fit
+func mp4Boxes(data []byte) []mp4Box {
+	var boxes []mp4Box
+	for len(data) >= 8 {
+		size, headerSize := uint64(binary.BigEndian.Uint32(data)), uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return boxes
+			}
+			size, headerSize = binary.BigEndian.Uint64(data[8:]), 16
+		}
+		if size < headerSize || size > uint64(len(data)) {
+			return boxes
+		}
+		boxes = append(boxes, mp4Box{kind: string(data[4:8]), payload: data[headerSize:size]})
+		data = data[size:]
+	}
+	return boxes
+}
+
+// metaChildren skips the version and flags of a meta box. QuickTime files
+// leave them out, which shows as the handler box starting straight away.
+func metaChildren(payload []byte) []byte {
+	if len(payload) >= 8 && string(payload[4:8]) == "hdlr" {
+		return payload
+	}
+	return skip(payload, 4)
+}
+
+// readIlst reads the iTunes metadata list out of a meta box's children,
+// including the ISRC that's stored as a free-form item
+func readIlst(meta []byte, fields map[string]string) {
+	for _, ilst := range mp4Boxes(meta) {
+		if ilst.kind != "ilst" {
+			continue
+		}
+		for _, item := range mp4Boxes(ilst.payload) {
+			name, ok := mp4Items[item.kind]
+			var value string
+			for _, child := range mp4Boxes(item.payload) {
+				switch child.kind {
+				case "name":
+					if string(skip(child.payload, 4)) == "ISRC" {
+						name, ok = "isrc", true
+					}
+				case "data":
+					// A type and a locale come before the value
+					if len(child.payload) >= 8 {
+						value = string(child.payload[8:])
+					}
+				}
+			}
+			if ok && fields[name] == "" {
+				fields[name] = value
+			}
+		}
+	}
+}
+
+// mvhdDuration reads the length of the movie from its header, which is in
+// version 0 or, with 64 bit times, version 1
+func mvhdDuration(payload []byte) time.Duration {
+	var timescale, duration uint64
+	switch {
+	case len(payload) >= 20 && payload[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(payload[12:]))
+		duration = uint64(binary.BigEndian.Uint32(payload[16:]))
+	case len(payload) >= 32 && payload[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(payload[20:]))
+		duration = binary.BigEndian.Uint64(payload[24:])
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(duration * uint64(time.Second) / timescale)
+}
@@ -0,0 +1,97 @@
+package local
+
+import (
+	"testing"
+	"time"
+)
+
+func box(kind string, payload ...[]byte) []byte {
+	data := concat(payload...)
+	return concat(be32(uint32(8+len(data))), []byte(kind), data)
+}
+
+// largeBox uses the 64 bit size that follows the type when the size is 1
+func largeBox(kind string, payload []byte) []byte {
+	return concat(be32(1), []byte(kind), be64(uint64(16+len(payload))), payload)
+}
+
+func dataBox(value string) []byte {
+	// A type of 1 is UTF-8 text, followed by the locale
+	return box("data", be32(1), be32(0), []byte(value))
+}
+
+func freeform(name, value string) []byte {
+	return box("----",
+		box("mean", be32(0), []byte("com.apple.iTunes")),
+		box("name", be32(0), []byte(name)),
+		dataBox(value),
+	)
+}
+
+func mvhd0(timescale, duration uint32) []byte {
+	return box("mvhd", []byte{0, 0, 0, 0}, make([]byte, 8), be32(timescale), be32(duration), make([]byte, 80))
+}
+
+func mvhd1(timescale uint32, duration uint64) []byte {
+	return box("mvhd", []byte{1, 0, 0, 0}, make([]byte, 16), be32(timescale), be64(duration), make([]byte, 80))
+}
+
+func hdlr() []byte {
+	return box("hdlr", make([]byte, 8), []byte("mdir"), make([]byte, 13))
+}
+
+func ftyp() []byte {
+	return box("ftyp", []byte("M4A "), be32(0), []byte("M4A mp42isom"))
+}
+
+func mp4Tests() []tagsTest {
+	return []tagsTest{
+		{
+			name: "udta meta with free-form ISRC",
+			file: "a.m4a",
+			data: concat(ftyp(), box("mdat", make([]byte, 5000)), box("moov",
+				mvhd0(1000, 185500),
+				box("trak", box("tkhd", make([]byte, 84))),
+				box("udta", box("meta", be32(0), hdlr(), box("ilst",
+					box("\xa9nam", dataBox("M4A Song")),
+					box("\xa9ART", dataBox("M4A Artist")),
+					box("\xa9alb", dataBox("M4A Album")),
+					box("\xa9day", dataBox("2011")),
+					freeform("MusicBrainz Track Id", "not an ISRC"),
+					freeform("ISRC", "usrc17607839"),
+				))),
+			)),
+			want: Tags{Title: "M4A Song", Artist: "M4A Artist", Album: "M4A Album", ISRC: "USRC17607839", Duration: seconds(185.5)},
+		},
+		{
+			name: "QuickTime meta, 64 bit sizes and times",
+			file: "b.mp4",
+			data: concat(ftyp(), largeBox("mdat", make([]byte, 3000)), box("moov",
+				mvhd1(44100, 44100*240),
+				box("meta", hdlr(), box("ilst",
+					box("\xa9nam", dataBox("Movie Song")),
+					box("aART", dataBox("Album Artist")),
+				)),
+			)),
+			want: Tags{Title: "Movie Song", Artist: "Album Artist", Duration: 240 * time.Second},
+		},
+		{
+			name: "movie box first and no tags",
+			file: "Some Artist - Some Title.m4b",
+			data: concat(ftyp(), box("moov", mvhd0(600, 600*42)), box("mdat", make([]byte, 100))),
+			want: Tags{Title: "Some Title", Artist: "Some Artist", Duration: 42 * time.Second},
+		},
+	}
+}
+
+func TestReadMP4(t *testing.T) {
+	runTagsTests(t, mp4Tests())
+	runErrorTests(t, []errorTest{
+		{"no ftyp", "a.m4a", concat(box("moov", mvhd0(1000, 1000)))},
+		{"empty", "b.m4a", nil},
+		{"no movie box", "c.m4a", concat(ftyp(), box("mdat", make([]byte, 100)))},
+		{"box running to the end", "d.m4a", concat(ftyp(), be32(0), []byte("mdat"), make([]byte, 100))},
+		{"size smaller than its header", "e.m4a", concat(ftyp(), be32(4), []byte("free"))},
+		{"truncated large size", "f.m4a", concat(ftyp(), be32(1), []byte("mdat"), []byte{0, 0})},
+	})
+}
@@ -0,0 +1,123 @@
+package local
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxOggHeaders bounds how much is read looking for the comment packet, which
+// can hold cover art
+const maxOggHeaders = 16 << 20
+
+func readOgg(file *os.File) (*Tags, error) {
+	packets, serial, err := oggHeaderPackets(file, 2)
+	if err != nil {
+		return nil, err
+	}
+	id, comments := packets[0], packets[1]
+
+	var sampleRate, preSkip int64
+	switch {
+	case bytes.HasPrefix(id, []byte("\x01vorbis")) && len(id) >= 16:
+		sampleRate = int64(binary.LittleEndian.Uint32(id[12:16]))
+		comments = bytes.TrimPrefix(comments, []byte("\x03vorbis"))
+	case bytes.HasPrefix(id, []byte("OpusHead")) && len(id) >= 12:
+		// Opus positions always count 48 kHz samples
+		sampleRate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(id[10:12]))
+		comments = bytes.TrimPrefix(comments, []byte("OpusTags"))
+	default:
+		return nil, errors.New("Only Vorbis and Opus streams are supported")
+	}
+
+	tags := tagsFromFields(readVorbisComments(comments))
+	if granule := lastGranule(file, serial); granule > preSkip && sampleRate > 0 {
+		tags.Duration = time.Duration((granule - preSkip) * int64(time.Second) / sampleRate)
+	}
+	return tags, nil
+}
+
+// oggHeaderPackets reassembles the first n packets of the first stream in the
+// file, which for Vorbis and Opus are the identification and comment headers
+func oggHeaderPackets(r io.ReaderAt, n int) ([][]byte, uint32, error) {
+	var packets [][]byte
+	var packet []byte
+	var serial uint32
+	var offset int64
+	header := make([]byte, 27)
+	for offset < maxOggHeaders {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return nil, 0, errors.New("Not an Ogg file, or too short")
+		}
+		if string(header[:4]) != "OggS" {
+			return nil, 0, errors.New("Not an Ogg file")
+		}
+		pageSerial := binary.LittleEndian.Uint32(header[14:18])
+		segments := make([]byte, header[26])
+		if _, err := r.ReadAt(segments, offset+27); err != nil {
+			return nil, 0, errors.WithMessage(err, "Failed to read Ogg page")
+		}
+		size := 0
+		for _, segment := range segments {
+			size += int(segment)
+		}
+		body := make([]byte, size)
+		if _, err := r.ReadAt(body, offset+27+int64(len(segments))); err != nil {
+			return nil, 0, errors.WithMessage(err, "Failed to read Ogg page")
+		}
+		if offset == 0 {
+			serial = pageSerial
+		}
+		offset += 27 + int64(len(segments)) + int64(size)
+
+		// Pages of other streams multiplexed in are skipped
+		if pageSerial != serial {
+			continue
+		}
+		for _, segment := range segments {
+			packet = append(packet, body[:segment]...)
+			body = body[segment:]
+			// A segment shorter than 255 bytes ends a packet
+			if segment < 255 {
+				packets = append(packets, packet)
+				packet = nil
+				if len(packets) == n {
+					return packets, serial, nil
+				}
+			}
+		}
+	}
+	return nil, 0, errors.New("Ogg headers are too large")
+}
+
+// lastGranule finds the granule position of the stream's last page, which is
+// the number of samples in it
+func lastGranule(file *os.File, serial uint32) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	start := info.Size() - 64*1024
+	if start < 0 {
+		start = 0
+	}
+	buf := make([]byte, info.Size()-start)
+	if _, err := file.ReadAt(buf, start); err != nil && err != io.EOF {
+		return 0
+	}
+	for i := bytes.LastIndex(buf, []byte("OggS")); i >= 0; i = bytes.LastIndex(buf[:i], []byte("OggS")) {
+		if i+27 > len(buf) {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(buf[i+6 : i+14]))
+		if binary.LittleEndian.Uint32(buf[i+14:i+18]) == serial && granule > 0 {
+			return granule
+		}
+	}
+	return 0
+}
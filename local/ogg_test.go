@@ -0,0 +1,121 @@
+package local
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Ogg page header types
+const (
+	oggContinued = 0x01
+	oggFirst     = 0x02
+	oggLast      = 0x04
+)
+
+// oggPage builds a page from a segment table and the body it describes
+func oggPage(headerType byte, granule int64, serial, sequence uint32, segments []byte, body []byte) []byte {
+	return concat([]byte("OggS"), []byte{0, headerType}, le64(uint64(granule)), le32(serial), le32(sequence),
+		make([]byte, 4), []byte{byte(len(segments))}, segments, body)
+}
+
+// lace is the segment table for whole packets of the given sizes
+func lace(sizes ...int) []byte {
+	var segments []byte
+	for _, n := range sizes {
+		for ; n >= 255; n -= 255 {
+			segments = append(segments, 255)
+		}
+		segments = append(segments, byte(n))
+	}
+	return segments
+}
+
+// oggPackets is a page holding whole packets
+func oggPackets(headerType byte, granule int64, serial, sequence uint32, packets ...[]byte) []byte {
+	var sizes []int
+	for _, packet := range packets {
+		sizes = append(sizes, len(packet))
+	}
+	return oggPage(headerType, granule, serial, sequence, lace(sizes...), concat(packets...))
+}
+
+func vorbisID(sampleRate uint32) []byte {
+	return concat([]byte("\x01vorbis"), le32(0), []byte{2}, le32(sampleRate), make([]byte, 12), []byte{0xB8, 0x01})
+}
+
+func opusHead(preSkip uint16) []byte {
+	return concat([]byte("OpusHead"), []byte{1, 2}, le16(preSkip), le32(44100), []byte{0, 0, 0})
+}
+
+func oggTests() []tagsTest {
+	comments := concat([]byte("\x03vorbis"), vorbisComments(
+		"TITLE=Ogg Song",
+		"ARTIST=Ogg Artist",
+		"ALBUM=Ogg Album",
+		"COVER="+string(bytes.Repeat([]byte("x"), 600)),
+	), []byte{1})
+	// The comment packet is split after two full segments, with a page of
+	// another stream in between
+	first, rest := comments[:510], comments[510:]
+
+	return []tagsTest{
+		{
+			name: "Vorbis with comments across pages",
+			file: "a.ogg",
+			data: concat(
+				oggPackets(oggFirst, 0, 7, 0, vorbisID(48000)),
+				oggPackets(oggFirst, 0, 9, 0, []byte("another stream")),
+				oggPage(0, -1, 7, 1, []byte{255, 255}, first),
+				oggPage(oggContinued, 0, 7, 2, lace(len(rest)), rest),
+				oggPackets(0, 48000*30, 7, 3, []byte("audio")),
+				oggPackets(oggLast, 48000*65, 7, 4, []byte("audio")),
+				oggPackets(oggLast, 48000*99, 9, 1, []byte("other stream's end")),
+			),
+			want: Tags{Title: "Ogg Song", Artist: "Ogg Artist", Album: "Ogg Album", Duration: 65 * time.Second},
+		},
+		{
+			name: "Vorbis headers on one page",
+			file: "b.oga",
+			data: concat(
+				oggPackets(oggFirst, 0, 1, 0, vorbisID(44100), concat([]byte("\x03vorbis"), vorbisComments("title=Same Page"), []byte{1})),
+				oggPackets(oggLast, 44100*3, 1, 1, []byte("audio")),
+			),
+			want: Tags{Title: "Same Page", Duration: 3 * time.Second},
+		},
+		{
+			name: "Opus with pre-skip",
+			file: "c.opus",
+			data: concat(
+				oggPackets(oggFirst, 0, 3, 0, opusHead(312)),
+				oggPackets(0, 0, 3, 1, concat([]byte("OpusTags"), vorbisComments("title=Opus Song", "artist=Opus Artist", "isrc=USRC17607839"))),
+				oggPackets(oggLast, 48000*30+312, 3, 2, []byte("audio")),
+			),
+			want: Tags{Title: "Opus Song", Artist: "Opus Artist", ISRC: "USRC17607839", Duration: 30 * time.Second},
+		},
+	}
+}
+
+func TestReadOgg(t *testing.T) {
+	runTagsTests(t, oggTests())
+	runErrorTests(t, []errorTest{
+		{"not Ogg", "a.ogg", []byte("fLaC and more bytes than a page header")},
+		{"empty", "b.ogg", nil},
+		{"only the first page", "c.ogg", oggPackets(oggFirst, 0, 1, 0, vorbisID(48000))},
+		{"Theora", "d.ogg", oggPackets(oggFirst, 0, 1, 0, []byte("\x80theora"), []byte("\x81theora"))},
+		{"truncated page", "e.opus", oggPackets(oggFirst, 0, 1, 0, opusHead(0))[:40]},
+	})
+}
+
+// A packet of exactly 255 bytes ends with an empty segment
+func TestOggHeaderPacketsEndingOnFullSegment(t *testing.T) {
+	packet := bytes.Repeat([]byte("x"), 255)
+	data := oggPackets(oggFirst, 0, 1, 0, packet, []byte("next"))
+	packets, serial, err := oggHeaderPackets(bytes.NewReader(data), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 1 || len(packets) != 2 || len(packets[0]) != 255 || string(packets[1]) != "next" {
+		t.Errorf("Packets = %d bytes and %q, serial %d", len(packets[0]), packets[1], serial)
+	}
+}
@@ -0,0 +1,105 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Tags are what's read from a music file to find it on Spotify. Fields the
+// file doesn't have are left empty.
+type Tags struct {
+	Title    string        `json:"title"`
+	Artist   string        `json:"artist"`
+	Album    string        `json:"album"`
+	ISRC     string        `json:"isrc,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ErrUnsupported is returned for files that aren't MP3, FLAC, Ogg or MP4 audio
+var ErrUnsupported = errors.New("Unsupported file type")
+
+var readers = map[string]func(*os.File) (*Tags, error){
+	".mp3":  readMP3,
+	".flac": readFLAC,
+	".ogg":  readOgg,
+	".oga":  readOgg,
+	".opus": readOgg,
+	".m4a":  readMP4,
+	".mp4":  readMP4,
+	".m4b":  readMP4,
+}
+
+// Supported reports whether the file's extension is one Read handles
+func Supported(path string) bool {
+	_, ok := readers[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// Read reads the tags and duration of a music file. A missing title, and
+// artist, are taken from the file name when it looks like "Artist - Title".
+func Read(path string) (*Tags, error) {
+	read, ok := readers[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tags, err := read(file)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed to read tags of %s", path)
+	}
+	tags.Title = strings.TrimSpace(tags.Title)
+	tags.Artist = strings.TrimSpace(tags.Artist)
+	tags.Album = strings.TrimSpace(tags.Album)
+	tags.ISRC = strings.ToUpper(strings.Replace(strings.TrimSpace(tags.ISRC), "-", "", -1))
+	if tags.Title == "" {
+		tags.Artist, tags.Title = fromFileName(path, tags.Artist)
+	}
+	return tags, nil
+}
+
+// Scan finds the supported music files under dir, sorted by path
+func Scan(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && Supported(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to scan directory")
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// trackNumber matches the track numbers file names often start with, as in "01 - " or "1. "
+var trackNumber = regexp.MustCompile(`^\d{1,3}\s*[-.)_]?\s+`)
+
+// fromFileName guesses the artist and title from a file name like
+// "01 Artist - Title.mp3", keeping artist if the name only has a title
+func fromFileName(path, artist string) (string, string) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.TrimSpace(trackNumber.ReplaceAllString(name, ""))
+	if parts := strings.SplitN(name, " - ", 2); len(parts) == 2 {
+		if artist == "" {
+			artist = strings.TrimSpace(parts[0])
+		}
+		return artist, strings.TrimSpace(parts[1])
+	}
+	return artist, name
+}
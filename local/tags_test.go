@@ -0,0 +1,177 @@
+package local
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// tagsTest is a file to read and the tags it should have
+type tagsTest struct {
+	name string
+	file string
+	data []byte
+	want Tags
+}
+
+// errorTest is a file that can't be read
+type errorTest struct {
+	name string
+	file string
+	data []byte
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// readFixture writes data to a file called name in dir and reads its tags
+func readFixture(t *testing.T, dir, name string, data []byte) (*Tags, error) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return Read(path)
+}
+
+func runTagsTests(t *testing.T, tests []tagsTest) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tags, err := readFixture(t, dir, test.file, test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *tags != test.want {
+				t.Errorf("Tags = %+v, want %+v", *tags, test.want)
+			}
+		})
+	}
+}
+
+func runErrorTests(t *testing.T, tests []errorTest) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if tags, err := readFixture(t, dir, test.file, test.data); err == nil {
+				t.Errorf("Read %+v, want an error", *tags)
+			}
+		})
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func be32(n uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, n)
+	return b
+}
+
+func be64(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
+func le16(n uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, n)
+	return b
+}
+
+func le32(n uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, n)
+	return b
+}
+
+func le64(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, n)
+	return b
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func TestFromFileName(t *testing.T) {
+	tests := []struct {
+		path, artist          string
+		wantArtist, wantTitle string
+	}{
+		{"Daft Punk - One More Time.mp3", "", "Daft Punk", "One More Time"},
+		{"01 - Daft Punk - One More Time.flac", "", "Daft Punk", "One More Time"},
+		{"07. Title Only.mp3", "", "", "Title Only"},
+		{"3) Tagged - Title.ogg", "Tag Artist", "Tag Artist", "Title"},
+		{"dir/2001 - Kubrick.m4a", "", "2001", "Kubrick"},
+	}
+	for _, test := range tests {
+		artist, title := fromFileName(test.path, test.artist)
+		if artist != test.wantArtist || title != test.wantTitle {
+			t.Errorf("fromFileName(%q, %q) = %q, %q, want %q, %q", test.path, test.artist, artist, title, test.wantArtist, test.wantTitle)
+		}
+	}
+}
+
+func TestReadUnsupported(t *testing.T) {
+	if _, err := Read("notes.txt"); err != ErrUnsupported {
+		t.Errorf("Read(notes.txt) error = %v, want ErrUnsupported", err)
+	}
+}
+
+// TestReadDamagedFiles reads every fixture cut short at many lengths, with
+// bytes overwritten at random, and as pure noise, none of which may panic
+func TestReadDamagedFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var tests []tagsTest
+	for _, group := range [][]tagsTest{id3Tests(), flacTests(), oggTests(), mp4Tests()} {
+		tests = append(tests, group...)
+	}
+	random := rand.New(rand.NewSource(1))
+	for _, test := range tests {
+		name := "damaged" + filepath.Ext(test.file)
+		for n := 0; n < len(test.data); n++ {
+			// Every length through the headers, then a sample of the rest
+			if n > 700 && n%97 != 0 {
+				continue
+			}
+			readFixture(t, dir, name, test.data[:n])
+		}
+		for i := 0; i < 200; i++ {
+			data := append([]byte(nil), test.data...)
+			for j := 0; j < 1+random.Intn(8); j++ {
+				data[random.Intn(len(data))] = byte(random.Intn(256))
+			}
+			readFixture(t, dir, name, data)
+		}
+	}
+	for _, ext := range []string{".mp3", ".flac", ".ogg", ".opus", ".m4a"} {
+		for i := 0; i < 50; i++ {
+			data := make([]byte, random.Intn(4096))
+			random.Read(data)
+			readFixture(t, dir, "noise"+ext, data)
+		}
+	}
+}
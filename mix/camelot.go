@@ -0,0 +1,76 @@
+package mix
+
+import "fmt"
+
+// Camelot is a position on the Camelot wheel, which lays out keys so that
+// neighbours mix well: one step around the wheel, or across between the
+// minor (A) and major (B) rings at the same number.
+type Camelot struct {
+	Number int
+	Letter byte
+}
+
+// FromKey converts a pitch class and mode as used in Spotify's audio features
+// into a Camelot key. Keys that weren't detected are -1 and come back as false.
+func FromKey(key, mode int) (Camelot, bool) {
+	if key < 0 || key > 11 {
+		return Camelot{}, false
+	}
+	if mode == 1 {
+		return Camelot{Number: majorNumber(key), Letter: 'B'}, true
+	}
+	// A minor key sits on the same number as its relative major, three semitones up
+	return Camelot{Number: majorNumber((key + 3) % 12), Letter: 'A'}, true
+}
+
+// majorNumber walks the circle of fifths starting from C major at 8B
+func majorNumber(key int) int {
+	return (key*7+7)%12 + 1
+}
+
+func (c Camelot) String() string {
+	if c.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%c", c.Number, c.Letter)
+}
+
+// MarshalText writes keys in the usual notation, like 8A
+func (c Camelot) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Clash scores how badly two keys mix, from 0 for the same key up to 7 for
+// opposite sides of the wheel
+func Clash(a, b Camelot) int {
+	steps := a.Number - b.Number
+	if steps < 0 {
+		steps = -steps
+	}
+	if steps > 6 {
+		steps = 12 - steps
+	}
+	switch {
+	case steps == 0 && a.Letter == b.Letter:
+		return 0
+	case steps == 1 && a.Letter == b.Letter, steps == 0:
+		return 1
+	case steps == 2 && a.Letter == b.Letter, steps == 1:
+		// An energy boost or a diagonal move, which works but is noticeable
+		return 2
+	}
+	return steps + 1
+}
+
+// Transition describes how two keys mix
+func Transition(a, b Camelot) string {
+	switch Clash(a, b) {
+	case 0:
+		return "same key"
+	case 1:
+		return "harmonic"
+	case 2:
+		return "energy shift"
+	}
+	return "clash"
+}
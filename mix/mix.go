@@ -0,0 +1,354 @@
+package mix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// tempoWeight is the cost of a 1% tempo change, so 8% weighs as much as an energy shift
+	tempoWeight = 0.25
+	// energyWeight is the cost of missing the energy curve by 0.1
+	energyWeight = 10.0
+	// maxGreedyStarts caps how many starting tracks the greedy pass tries
+	maxGreedyStarts = 50
+	// maxPasses caps how many rounds of local search are run
+	maxPasses = 20
+	// arcPeak is how far into an arc mix the energy peaks
+	arcPeak = 0.7
+)
+
+// Order arranges tracks into a mix that keeps key clashes and tempo jumps
+// small while following the energy curve. A greedy pass from several
+// starting tracks picks a first order, which local search then improves by
+// reversing stretches of the mix and moving single tracks.
+func Order(tracks []Track, options Options) (*Result, error) {
+	switch options.Curve {
+	case CurveNone, CurveBuild, CurveArc, CurveCooldown:
+	default:
+		return nil, fmt.Errorf("Unknown energy curve %q", options.Curve)
+	}
+	if options.MinBPM > 0 && options.MaxBPM > 0 && options.MinBPM > options.MaxBPM {
+		return nil, fmt.Errorf("Minimum BPM must not be more than maximum BPM")
+	}
+
+	result := &Result{}
+	var mixable []Track
+	for _, track := range tracks {
+		if track.HasFeatures && inRange(track.Tempo, options) {
+			mixable = append(mixable, track)
+		} else {
+			result.Excluded = append(result.Excluded, track)
+		}
+	}
+	if len(mixable) == 0 {
+		return result, nil
+	}
+
+	m := newMixer(mixable, options.Curve)
+	order := m.greedy()
+	m.improve(order)
+	result.Cost = m.cost(order)
+
+	for i, index := range order {
+		step := Step{
+			Track:        mixable[index],
+			TargetEnergy: m.target(i),
+		}
+		if i > 0 {
+			previous := mixable[order[i-1]]
+			step.KeyClash = Clash(previous.Key, step.Track.Key)
+			step.Transition = Transition(previous.Key, step.Track.Key)
+			step.TempoChange = tempoChange(previous.Tempo, step.Track.Tempo)
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	return result, nil
+}
+
+// inRange reports whether a tempo, or half or double it, is within the BPM range
+func inRange(tempo float64, options Options) bool {
+	for _, t := range []float64{tempo, tempo / 2, tempo * 2} {
+		if (options.MinBPM <= 0 || t >= options.MinBPM) && (options.MaxBPM <= 0 || t <= options.MaxBPM) {
+			return true
+		}
+	}
+	return false
+}
+
+// tempoChange is the percentage change in tempo from one track to the next,
+// counting whichever of half, normal or double time is closest
+func tempoChange(from, to float64) float64 {
+	if from <= 0 || to <= 0 {
+		return 0
+	}
+	best := to - from
+	for _, t := range []float64{to / 2, to * 2} {
+		if math.Abs(t-from) < math.Abs(best) {
+			best = t - from
+		}
+	}
+	return best / from * 100
+}
+
+type mixer struct {
+	tracks     []Track
+	curve      string
+	transition [][]float64
+	low, high  float64
+}
+
+func newMixer(tracks []Track, curve string) *mixer {
+	m := &mixer{
+		tracks:     tracks,
+		curve:      curve,
+		transition: make([][]float64, len(tracks)),
+		low:        math.Inf(1),
+		high:       math.Inf(-1),
+	}
+	for i, a := range tracks {
+		m.transition[i] = make([]float64, len(tracks))
+		for j, b := range tracks {
+			m.transition[i][j] = float64(Clash(a.Key, b.Key)) + math.Abs(tempoChange(a.Tempo, b.Tempo))*tempoWeight
+		}
+		m.low = math.Min(m.low, a.Energy)
+		m.high = math.Max(m.high, a.Energy)
+	}
+	return m
+}
+
+// target is the energy the curve wants at a position in the mix
+func (m *mixer) target(position int) float64 {
+	if m.curve == CurveNone {
+		return 0
+	}
+	t := 0.0
+	if len(m.tracks) > 1 {
+		t = float64(position) / float64(len(m.tracks)-1)
+	}
+	span := m.high - m.low
+	switch m.curve {
+	case CurveBuild:
+		return m.low + span*t
+	case CurveCooldown:
+		return m.high - span*t
+	}
+	// An arc builds up to its peak and then cools down part of the way
+	if t <= arcPeak {
+		return m.low + span*t/arcPeak
+	}
+	return m.high - span*0.6*(t-arcPeak)/(1-arcPeak)
+}
+
+func (m *mixer) placement(track, position int) float64 {
+	if m.curve == CurveNone {
+		return 0
+	}
+	return math.Abs(m.tracks[track].Energy-m.target(position)) * energyWeight
+}
+
+func (m *mixer) cost(order []int) float64 {
+	total := 0.0
+	for i, track := range order {
+		total += m.placement(track, i)
+		if i > 0 {
+			total += m.transition[order[i-1]][track]
+		}
+	}
+	return total
+}
+
+// greedy builds an order from each of the most promising first tracks by
+// always taking the cheapest next one, and keeps the best
+func (m *mixer) greedy() []int {
+	starts := make([]int, len(m.tracks))
+	for i := range starts {
+		starts[i] = i
+	}
+	sort.SliceStable(starts, func(i, j int) bool {
+		return m.placement(starts[i], 0) < m.placement(starts[j], 0)
+	})
+	if len(starts) > maxGreedyStarts {
+		starts = starts[:maxGreedyStarts]
+	}
+
+	var best []int
+	bestCost := math.Inf(1)
+	for _, start := range starts {
+		used := make([]bool, len(m.tracks))
+		order := []int{start}
+		used[start] = true
+		for len(order) < len(m.tracks) {
+			last := order[len(order)-1]
+			next := -1
+			nextCost := math.Inf(1)
+			for candidate := range m.tracks {
+				if used[candidate] {
+					continue
+				}
+				cost := m.transition[last][candidate] + m.placement(candidate, len(order))
+				if cost < nextCost {
+					next, nextCost = candidate, cost
+				}
+			}
+			order = append(order, next)
+			used[next] = true
+		}
+		if cost := m.cost(order); cost < bestCost {
+			best, bestCost = order, cost
+		}
+	}
+	return best
+}
+
+// improve reverses stretches of the order and moves single tracks elsewhere
+// for as long as that makes the mix cheaper. Rather than costing every
+// candidate order in full, each change is priced by the transitions at its
+// ends and the placements it shifts, using running sums over the current
+// order, which keeps a pass to O(n²).
+func (m *mixer) improve(order []int) {
+	s := &search{mixer: m, order: order, candidate: make([]int, len(order))}
+	s.update()
+	for pass := 0; pass < maxPasses; pass++ {
+		reversed := s.reversals()
+		moved := s.moves()
+		if !reversed && !moved {
+			return
+		}
+	}
+}
+
+// search is local search over an order, with running sums of its costs
+type search struct {
+	*mixer
+	order     []int
+	candidate []int
+	// forward[k] and backward[k] sum the transitions between the first k
+	// tracks going forwards and going backwards
+	forward, backward []float64
+	// placed[k] sums the placements of the first k tracks, and earlier[k]
+	// and later[k] what they would be one position earlier or later
+	placed, earlier, later []float64
+}
+
+func (s *search) update() {
+	n := len(s.order)
+	s.forward, s.backward = make([]float64, n), make([]float64, n)
+	for k := 1; k < n; k++ {
+		s.forward[k] = s.forward[k-1] + s.transition[s.order[k-1]][s.order[k]]
+		s.backward[k] = s.backward[k-1] + s.transition[s.order[k]][s.order[k-1]]
+	}
+	s.placed, s.earlier, s.later = make([]float64, n+1), make([]float64, n+1), make([]float64, n+1)
+	for k, track := range s.order {
+		s.placed[k+1] = s.placed[k] + s.placement(track, k)
+		s.earlier[k+1] = s.earlier[k]
+		if k > 0 {
+			s.earlier[k+1] += s.placement(track, k-1)
+		}
+		s.later[k+1] = s.later[k]
+		if k < n-1 {
+			s.later[k+1] += s.placement(track, k+1)
+		}
+	}
+}
+
+// at is the track at a position, or -1 past either end
+func (s *search) at(position int) int {
+	if position < 0 || position >= len(s.order) {
+		return -1
+	}
+	return s.order[position]
+}
+
+// link is the transition cost between two tracks, or 0 if either is missing
+func (s *search) link(from, to int) float64 {
+	if from < 0 || to < 0 {
+		return 0
+	}
+	return s.transition[from][to]
+}
+
+// reversals tries reversing every stretch of the order, keeping those that
+// help. Stretches are grouped by their middle, so growing one by a track at
+// each end only adds the placements of those two.
+func (s *search) reversals() bool {
+	n := len(s.order)
+	improved := false
+	for middle := 1; middle < 2*n-2; middle++ {
+		i, j := middle/2, middle/2+1
+		placed := 0.0
+		if middle%2 == 0 {
+			i--
+			placed = s.placement(s.order[middle/2], middle/2)
+		}
+		for ; i >= 0 && j < n; i, j = i-1, j+1 {
+			placed += s.placement(s.order[j], i) + s.placement(s.order[i], j)
+			before := s.link(s.at(i-1), s.order[i]) + s.link(s.order[j], s.at(j+1)) +
+				s.forward[j] - s.forward[i] + s.placed[j+1] - s.placed[i]
+			after := s.link(s.at(i-1), s.order[j]) + s.link(s.order[i], s.at(j+1)) +
+				s.backward[j] - s.backward[i] + placed
+			if after < before-1e-9 {
+				reverse(s.order[i : j+1])
+				s.update()
+				improved = true
+				break
+			}
+		}
+	}
+	return improved
+}
+
+// moves tries moving every track to every other position, keeping the moves
+// that help
+func (s *search) moves() bool {
+	improved := false
+	for from := range s.order {
+		for to := range s.order {
+			if from == to {
+				continue
+			}
+			track := s.order[from]
+			var before, after float64
+			if from < to {
+				// The tracks after it up to to shift one position earlier
+				before = s.link(s.at(from-1), track) + s.link(track, s.order[from+1]) + s.link(s.order[to], s.at(to+1)) +
+					s.placement(track, from) + s.placed[to+1] - s.placed[from+1]
+				after = s.link(s.at(from-1), s.order[from+1]) + s.link(s.order[to], track) + s.link(track, s.at(to+1)) +
+					s.placement(track, to) + s.earlier[to+1] - s.earlier[from+1]
+			} else {
+				// The tracks from to up to it shift one position later
+				before = s.link(s.at(to-1), s.order[to]) + s.link(s.order[from-1], track) + s.link(track, s.at(from+1)) +
+					s.placement(track, from) + s.placed[from] - s.placed[to]
+				after = s.link(s.at(to-1), track) + s.link(track, s.order[to]) + s.link(s.order[from-1], s.at(from+1)) +
+					s.placement(track, to) + s.later[from] - s.later[to]
+			}
+			if after < before-1e-9 {
+				move(s.candidate, s.order, from, to)
+				copy(s.order, s.candidate)
+				s.update()
+				improved = true
+			}
+		}
+	}
+	return improved
+}
+
+func reverse(order []int) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
+
+// move writes order into dst with the track at from moved to position to
+func move(dst, order []int, from, to int) {
+	track := order[from]
+	rest := dst[:0]
+	for i, t := range order {
+		if i != from {
+			rest = append(rest, t)
+		}
+	}
+	copy(dst[to+1:], rest[to:len(order)-1])
+	dst[to] = track
+}
@@ -0,0 +1,65 @@
+package mix
+
+// Energy curves a mix can follow
+const (
+	CurveNone     = ""
+	CurveBuild    = "build"
+	CurveArc      = "arc"
+	CurveCooldown = "cooldown"
+)
+
+// Curves are the energy curves accepted in Options
+var Curves = []string{CurveBuild, CurveArc, CurveCooldown}
+
+// Track is a playlist item along with what the mix needs to know about it
+type Track struct {
+	ID      string  `json:"id"`
+	URI     string  `json:"uri"`
+	Name    string  `json:"name"`
+	Artists string  `json:"artists"`
+	Tempo   float64 `json:"tempo"`
+	Energy  float64 `json:"energy"`
+	Key     Camelot `json:"key"`
+	// HasFeatures is false for local files, episodes and tracks Spotify has
+	// no audio features for, which can't be placed in the mix
+	HasFeatures bool `json:"has_features"`
+}
+
+// Options -
+type Options struct {
+	// MinBPM and MaxBPM limit the mix to tracks in a tempo range, counting
+	// half and double time. Zero leaves that end open.
+	MinBPM float64
+	MaxBPM float64
+	// Curve is the energy curve to follow over the mix, if any
+	Curve string
+}
+
+// Step is a track in the mix and how it follows the one before it
+type Step struct {
+	Track Track `json:"track"`
+	// KeyClash and TempoChange are zero for the first track
+	KeyClash    int     `json:"key_clash"`
+	Transition  string  `json:"transition,omitempty"`
+	TempoChange float64 `json:"tempo_change"`
+	// TargetEnergy is where the energy curve wanted this position to be
+	TargetEnergy float64 `json:"target_energy,omitempty"`
+}
+
+// Result -
+type Result struct {
+	Steps []Step `json:"steps"`
+	// Excluded are the tracks left out of the mix, in their original order.
+	// They go after the mix so applying it never drops anything.
+	Excluded []Track `json:"excluded"`
+	Cost     float64 `json:"cost"`
+}
+
+// Tracks returns the mixed tracks followed by the excluded ones
+func (r *Result) Tracks() []Track {
+	tracks := make([]Track, 0, len(r.Steps)+len(r.Excluded))
+	for _, step := range r.Steps {
+		tracks = append(tracks, step.Track)
+	}
+	return append(tracks, r.Excluded...)
+}
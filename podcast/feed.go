@@ -0,0 +1,102 @@
+package podcast
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwseger/spotify-cli/feed"
+	"github.com/cwseger/spotify-cli/spotify"
+)
+
+// pageSize is how many episodes are requested at once, the most Spotify allows
+const pageSize = 50
+
+// Options -
+type Options struct {
+	// Since leaves out episodes released before this day
+	Since time.Time
+	// Market is the country to get episodes in, needed without a user token
+	Market string
+}
+
+// Feed makes a feed of a show's episodes, newest first. Episodes come from
+// Spotify newest first too, so paging stops at the first one that's already
+// in previous, or was released before options.Since, and previous's items
+// are kept after the new ones. It returns how many episodes were added.
+func Feed(ctx context.Context, client spotify.Client, show *spotify.Show, previous *feed.Feed, options Options) (*feed.Feed, int, error) {
+	f := &feed.Feed{
+		Title:       show.Name,
+		Link:        "https://open.spotify.com/show/" + show.ID,
+		Description: show.Description,
+		Updated:     time.Now(),
+		Image:       largestImage(show.Images),
+	}
+	known := map[string]bool{}
+	if previous != nil {
+		for _, item := range previous.Items {
+			known[item.ID] = true
+		}
+	}
+	since := ""
+	if !options.Since.IsZero() {
+		since = options.Since.Format("2006-01-02")
+	}
+
+	added := 0
+	for offset := 0; ; offset += pageSize {
+		page, err := client.GetShowEpisodes(ctx, show.ID, options.Market, pageSize, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, episode := range page.Items {
+			// Episodes that aren't available come back empty
+			if episode.ID == "" {
+				continue
+			}
+			if known[episode.URI] || episode.ReleaseDate < since {
+				return withPrevious(f, previous, since), added, nil
+			}
+			f.Items = append(f.Items, item(episode))
+			added++
+		}
+		if page.Next == "" || len(page.Items) == 0 {
+			return withPrevious(f, previous, since), added, nil
+		}
+	}
+}
+
+// withPrevious adds the items of the previous feed that aren't from before since
+func withPrevious(f, previous *feed.Feed, since string) *feed.Feed {
+	if previous == nil {
+		return f
+	}
+	for _, item := range previous.Items {
+		if since != "" && !item.Published.IsZero() && item.Published.Format("2006-01-02") < since {
+			continue
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f
+}
+
+func item(episode spotify.Episode) feed.Item {
+	// Episodes without a date Spotify can give are left undated
+	published, _ := spotify.ParseReleaseDate(episode.ReleaseDate, episode.ReleaseDatePrecision)
+	return feed.Item{
+		ID:          episode.URI,
+		Title:       episode.Name,
+		Link:        "https://open.spotify.com/episode/" + episode.ID,
+		Description: episode.Description,
+		Published:   published,
+		Duration:    time.Duration(episode.DurationMS) * time.Millisecond,
+		Image:       largestImage(episode.Images),
+	}
+}
+
+// largestImage is the URL of the first image, which Spotify lists largest first
+func largestImage(images []spotify.Image) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return images[0].URL
+}
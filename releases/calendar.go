@@ -0,0 +1,106 @@
+package releases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/ical"
+	"github.com/cwseger/spotify-cli/spotify"
+)
+
+// Collect gets every album and single by the artists, oldest first. Releases
+// by several of the artists are only listed once.
+func Collect(ctx context.Context, client spotify.Client, artists []Artist, options Options) ([]Release, error) {
+	discographies, err := fetchDiscographies(ctx, client, artists, options)
+	if err != nil {
+		return nil, err
+	}
+	var collected []Release
+	seen := map[string]bool{}
+	for i, artist := range artists {
+		for _, album := range discographies[i] {
+			if album.AlbumGroup != "album" && album.AlbumGroup != "single" {
+				continue
+			}
+			key := releaseKey(album)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			collected = append(collected, Release{Album: album, Artist: artist})
+		}
+	}
+	sort.SliceStable(collected, func(i, j int) bool {
+		return collected[i].Album.ReleaseDate < collected[j].Album.ReleaseDate
+	})
+	return collected, nil
+}
+
+// Between keeps the releases that came out from one day up to but not
+// including another, either of which can be zero to leave that end open
+func Between(releases []Release, from, to time.Time) []Release {
+	var kept []Release
+	for _, release := range releases {
+		day, ok := spotify.ParseReleaseDate(release.Album.ReleaseDate, release.Album.ReleaseDatePrecision)
+		if !ok {
+			continue
+		}
+		date := day.Format("2006-01-02")
+		if !from.IsZero() && date < from.Format("2006-01-02") {
+			continue
+		}
+		if !to.IsZero() && date >= to.Format("2006-01-02") {
+			continue
+		}
+		kept = append(kept, release)
+	}
+	return kept
+}
+
+// Calendar makes an all-day event for every release on its release date.
+// When Spotify only knows the month or year the event is on the first day of
+// it and says so in the description.
+func Calendar(name string, releases []Release) *ical.Calendar {
+	calendar := &ical.Calendar{Name: name}
+	for _, release := range releases {
+		album := release.Album
+		day, ok := spotify.ParseReleaseDate(album.ReleaseDate, album.ReleaseDatePrecision)
+		if !ok {
+			continue
+		}
+		artists := make([]string, 0, len(album.Artists))
+		for _, artist := range album.Artists {
+			artists = append(artists, artist.Name)
+		}
+		link := "https://open.spotify.com/album/" + album.ID
+
+		description := fmt.Sprintf("%s with %d tracks", strings.Title(releaseType(album)), album.TotalTracks)
+		switch album.ReleaseDatePrecision {
+		case "year":
+			description += ", released sometime in " + day.Format("2006")
+		case "month":
+			description += ", released sometime in " + day.Format("January 2006")
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         album.ID + "@open.spotify.com",
+			Summary:     strings.Join(artists, ", ") + " - " + album.Name,
+			Description: description + "\n" + link,
+			URL:         link,
+			Start:       day,
+			End:         day.AddDate(0, 0, 1),
+		})
+	}
+	return calendar
+}
+
+// releaseType is the album group when the release came from an artist's
+// albums and the album type otherwise, as for browsed new releases
+func releaseType(album spotify.Album) string {
+	if album.AlbumGroup != "" {
+		return album.AlbumGroup
+	}
+	return album.AlbumType
+}
@@ -0,0 +1,33 @@
+package releases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/feed"
+)
+
+// Feed turns the recently reported releases into a feed, newest first
+func Feed(state *State) *feed.Feed {
+	f := &feed.Feed{
+		Title:       "New releases",
+		Link:        "https://open.spotify.com/",
+		Description: "New albums and singles from the artists you follow and watch",
+		Updated:     state.LastRun,
+	}
+	for _, release := range state.Recent {
+		album := release.Album
+		names := make([]string, len(album.Artists))
+		for i, artist := range album.Artists {
+			names[i] = artist.Name
+		}
+		f.Items = append(f.Items, feed.Item{
+			ID:          album.URI,
+			Title:       fmt.Sprintf("%s - %s", strings.Join(names, ", "), album.Name),
+			Link:        "https://open.spotify.com/album/" + album.ID,
+			Description: fmt.Sprintf("%s with %d tracks, released %s", strings.Title(album.AlbumGroup), album.TotalTracks, album.ReleaseDate),
+			Published:   release.FoundAt,
+		})
+	}
+	return f
+}
@@ -0,0 +1,40 @@
+package releases
+
+import (
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+)
+
+// Artist is an artist being watched for new releases
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Release is an album or single found by a check
+type Release struct {
+	Album spotify.Album `json:"album"`
+	// Artist is the watched artist the release was found for
+	Artist  Artist    `json:"artist"`
+	FoundAt time.Time `json:"found_at"`
+}
+
+// State is what the watcher remembers between runs
+type State struct {
+	LastRun time.Time `json:"last_run"`
+	// Seen holds a key for every release ever found, so each is only reported once
+	Seen map[string]time.Time `json:"seen"`
+	// Recent are the latest reported releases, newest first, kept for feeds
+	Recent []Release `json:"recent"`
+}
+
+// Options -
+type Options struct {
+	// Since reports releases from this day on instead of from the last run
+	Since time.Time
+	// Market only checks releases available in this country, if set
+	Market string
+	// Concurrency is how many artists are checked at once
+	Concurrency int
+}
@@ -0,0 +1,189 @@
+package releases
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/parallel"
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+)
+
+// maxRecent is how many reported releases are kept for feeds
+const maxRecent = 200
+
+// Check looks for albums and singles by the artists that haven't been seen
+// before and came out on or after the last run, or options.Since if set, and
+// records them in the state. The first check of a new state has nothing to
+// compare against, so unless Since is set it only records what exists.
+func Check(ctx context.Context, client spotify.Client, artists []Artist, state *State, options Options) ([]Release, error) {
+	discographies, err := fetchDiscographies(ctx, client, artists, options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cutoff := options.Since
+	if cutoff.IsZero() {
+		cutoff = state.LastRun
+	}
+	cutoffDay := ""
+	if !cutoff.IsZero() {
+		cutoffDay = cutoff.In(time.Local).Format("2006-01-02")
+	}
+
+	var found []Release
+	for i, artist := range artists {
+		for _, album := range discographies[i] {
+			if album.AlbumGroup != "album" && album.AlbumGroup != "single" {
+				continue
+			}
+			key := releaseKey(album)
+			if _, ok := state.Seen[key]; ok {
+				continue
+			}
+			state.Seen[key] = now
+			if cutoffDay == "" {
+				continue
+			}
+			// Release dates can be just a year or a month, so they're compared
+			// with the cutoff at the same precision
+			day := cutoffDay
+			if len(album.ReleaseDate) < len(day) {
+				day = day[:len(album.ReleaseDate)]
+			}
+			if album.ReleaseDate < day {
+				continue
+			}
+			found = append(found, Release{Album: album, Artist: artist, FoundAt: now})
+		}
+	}
+
+	recent := make([]Release, 0, len(found)+len(state.Recent))
+	for i := len(found) - 1; i >= 0; i-- {
+		recent = append(recent, found[i])
+	}
+	recent = append(recent, state.Recent...)
+	if len(recent) > maxRecent {
+		recent = recent[:maxRecent]
+	}
+	state.Recent = recent
+	state.LastRun = now
+	return found, nil
+}
+
+// releaseKey identifies a release by its main artist, name and track count
+// rather than its ID, since the same release is often listed under several
+// IDs for different regions
+func releaseKey(album spotify.Album) string {
+	artistID := ""
+	if len(album.Artists) > 0 {
+		artistID = album.Artists[0].ID
+	}
+	return artistID + "|" + strings.ToLower(strings.TrimSpace(album.Name)) + "|" + strconv.Itoa(album.TotalTracks)
+}
+
+// fetchDiscographies gets the releases of every artist, a few at a time,
+// returning them in the same order as artists
+func fetchDiscographies(ctx context.Context, client spotify.Client, artists []Artist, options Options) ([][]spotify.Album, error) {
+	discographies := make([][]spotify.Album, len(artists))
+	if err := parallel.ForEach(ctx, len(artists), options.Concurrency, func(ctx context.Context, i int) error {
+		out, err := client.GetArtistDiscography(ctx, artists[i].ID, options.Market)
+		if err != nil {
+			return errors.WithMessagef(err, "Failed to check %s", artists[i].Name)
+		}
+		discographies[i] = out.Albums
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return discographies, nil
+}
+
+// DefaultWatchlistPath is where the watchlist is read from unless another file is given
+func DefaultWatchlistPath() (string, error) {
+	dir, err := spotify.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watchlist.txt"), nil
+}
+
+// DefaultStatePath is where the state is kept unless another file is given
+func DefaultStatePath() (string, error) {
+	dir, err := spotify.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "releases.json"), nil
+}
+
+// ReadWatchlist reads artists from a file with one per line, given as a
+// name, ID, URI or link. Blank lines and lines starting with # are skipped.
+// A missing file is an empty watchlist.
+func ReadWatchlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to open watchlist")
+	}
+	defer file.Close()
+
+	var artists []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		artists = append(artists, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithMessage(err, "Failed to read watchlist")
+	}
+	return artists, nil
+}
+
+// LoadState reads the state at path, or returns an empty one if there is none yet
+func LoadState(path string) (*State, error) {
+	state := &State{Seen: map[string]time.Time{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read release state")
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.WithMessage(err, "Failed to unmarshal release state")
+	}
+	if state.Seen == nil {
+		state.Seen = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// Save -
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal release state")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithMessage(err, "Failed to create release state directory")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.WithMessage(err, "Failed to write release state")
+	}
+	return nil
+}
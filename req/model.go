@@ -1,5 +1,10 @@
 package req
 
+import (
+	"fmt"
+	"time"
+)
+
 // GetInput -
 type GetInput struct {
 	URL         string
@@ -13,7 +18,49 @@ type GetInput struct {
 type PostInput struct {
 	URL         string
 	Slugs       *map[string]string
+	QueryParams *map[string]string
 	Headers     *map[string]string
 	Body        *map[string]string
+	JSONBody    interface{}
+	Destination interface{}
+}
+
+// PutInput -
+type PutInput struct {
+	URL         string
+	Slugs       *map[string]string
+	QueryParams *map[string]string
+	Headers     *map[string]string
+	JSONBody    interface{}
+	Destination interface{}
+}
+
+// DeleteInput -
+type DeleteInput struct {
+	URL         string
+	Slugs       *map[string]string
+	QueryParams *map[string]string
+	Headers     *map[string]string
+	JSONBody    interface{}
 	Destination interface{}
 }
+
+// ResponseError is returned when a request completes with a non-2xx status code
+type ResponseError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// errorBody covers both the regular and the authentication error objects the Web API returns
+type errorBody struct {
+	Error            interface{} `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
@@ -1,21 +1,30 @@
 package req
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	neturl "net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// maxRetries is how many times a rate limited request is retried before giving up
+const maxRetries = 3
+
 // Requestor -
 type Requestor interface {
 	Get(ctx context.Context, input *GetInput) error
 	Post(ctx context.Context, input *PostInput) error
+	Put(ctx context.Context, input *PutInput) error
+	Delete(ctx context.Context, input *DeleteInput) error
 }
 
 // DefaultRequestor -
@@ -34,59 +43,113 @@ var _ Requestor = &DefaultRequestor{}
 
 // Get -
 func (r *DefaultRequestor) Get(ctx context.Context, input *GetInput) error {
-	url, err := r.replaceSlugsWithValues(input.URL, input.Slugs)
+	url, err := r.buildURL(input.URL, input.Slugs, input.QueryParams)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to replace slugs with values")
+		return err
 	}
-	url, err = r.addQueryParamsToURL(url, input.QueryParams)
-	if err != nil {
-		return errors.WithMessage(err, "Failed to add query params to url")
+
+	if err := r.do(ctx, http.MethodGet, url, input.Headers, nil, "", input.Destination); err != nil {
+		return errors.WithMessage(err, "Failed to execute GET request")
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// Post -
+func (r *DefaultRequestor) Post(ctx context.Context, input *PostInput) error {
+	url, err := r.buildURL(input.URL, input.Slugs, input.QueryParams)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to build new GET request with context")
+		return err
 	}
-	r.addHeadersToRequest(input.Headers, req)
 
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		return errors.WithMessage(err, "Failed to execute GET request")
+	var body []byte
+	var contentType string
+	if input.Body != nil {
+		bodyValues := neturl.Values{}
+		for k, v := range *input.Body {
+			bodyValues.Set(k, v)
+		}
+		body = []byte(bodyValues.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else {
+		body, contentType, err = r.encodeJSONBody(input.JSONBody)
+		if err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	if err := r.do(ctx, http.MethodPost, url, input.Headers, body, contentType, input.Destination); err != nil {
+		return errors.WithMessage(err, "Failed to execute POST request")
+	}
+	return nil
+}
+
+// Put -
+func (r *DefaultRequestor) Put(ctx context.Context, input *PutInput) error {
+	url, err := r.buildURL(input.URL, input.Slugs, input.QueryParams)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to read response body")
+		return err
+	}
+	body, contentType, err := r.encodeJSONBody(input.JSONBody)
+	if err != nil {
+		return err
 	}
 
-	if err := json.Unmarshal(body, input.Destination); err != nil {
-		return errors.WithMessage(err, "Failed to unmarshal response body")
+	if err := r.do(ctx, http.MethodPut, url, input.Headers, body, contentType, input.Destination); err != nil {
+		return errors.WithMessage(err, "Failed to execute PUT request")
 	}
 	return nil
 }
 
-// Post -
-func (r *DefaultRequestor) Post(ctx context.Context, input *PostInput) error {
-	url, err := r.replaceSlugsWithValues(input.URL, input.Slugs)
+// Delete -
+func (r *DefaultRequestor) Delete(ctx context.Context, input *DeleteInput) error {
+	url, err := r.buildURL(input.URL, input.Slugs, input.QueryParams)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to replace slugs with values")
+		return err
+	}
+	body, contentType, err := r.encodeJSONBody(input.JSONBody)
+	if err != nil {
+		return err
 	}
 
-	bodyValues := neturl.Values{}
-	for k, v := range *input.Body {
-		bodyValues.Set(k, v)
+	if err := r.do(ctx, http.MethodDelete, url, input.Headers, body, contentType, input.Destination); err != nil {
+		return errors.WithMessage(err, "Failed to execute DELETE request")
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(bodyValues.Encode()))
-	if err != nil {
-		return errors.WithMessage(err, "Failed to build new POST request with context")
+func (r *DefaultRequestor) do(ctx context.Context, method, url string, headers *map[string]string, body []byte, contentType string, destination interface{}) error {
+	for attempt := 0; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reader)
+		if err != nil {
+			return errors.WithMessage(err, "Failed to build new request with context")
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		r.addHeadersToRequest(headers, req)
+
+		err = r.execute(req, destination)
+		respErr, ok := err.(*ResponseError)
+		if !ok || respErr.StatusCode != http.StatusTooManyRequests || attempt >= maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(respErr.RetryAfter):
+		}
 	}
-	r.addHeadersToRequest(input.Headers, req)
+}
 
+func (r *DefaultRequestor) execute(req *http.Request, destination interface{}) error {
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return errors.WithMessage(err, "Failed to execute POST request")
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -95,12 +158,71 @@ func (r *DefaultRequestor) Post(ctx context.Context, input *PostInput) error {
 		return errors.WithMessage(err, "Failed to read response body")
 	}
 
-	if err := json.Unmarshal(body, input.Destination); err != nil {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return r.newResponseError(resp, body)
+	}
+
+	if destination == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, destination); err != nil {
 		return errors.WithMessage(err, "Failed to unmarshal response body")
 	}
 	return nil
 }
 
+func (r *DefaultRequestor) newResponseError(resp *http.Response, body []byte) *ResponseError {
+	respErr := &ResponseError{
+		StatusCode: resp.StatusCode,
+	}
+
+	var errBody errorBody
+	if err := json.Unmarshal(body, &errBody); err == nil {
+		switch e := errBody.Error.(type) {
+		case string:
+			respErr.Message = e
+			if errBody.ErrorDescription != "" {
+				respErr.Message += ": " + errBody.ErrorDescription
+			}
+		case map[string]interface{}:
+			if message, ok := e["message"].(string); ok {
+				respErr.Message = message
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		respErr.RetryAfter = time.Second
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			respErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return respErr
+}
+
+func (r *DefaultRequestor) buildURL(rawURL string, slugs, queryParams *map[string]string) (string, error) {
+	url, err := r.replaceSlugsWithValues(rawURL, slugs)
+	if err != nil {
+		return "", errors.WithMessage(err, "Failed to replace slugs with values")
+	}
+	url, err = r.addQueryParamsToURL(url, queryParams)
+	if err != nil {
+		return "", errors.WithMessage(err, "Failed to add query params to url")
+	}
+	return url, nil
+}
+
+func (r *DefaultRequestor) encodeJSONBody(jsonBody interface{}) ([]byte, string, error) {
+	if jsonBody == nil {
+		return nil, "", nil
+	}
+	body, err := json.Marshal(jsonBody)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "Failed to marshal request body")
+	}
+	return body, "application/json", nil
+}
+
 func (r *DefaultRequestor) replaceSlugsWithValues(rawURL string, slugs *map[string]string) (string, error) {
 	if slugs == nil {
 		return rawURL, nil
@@ -143,6 +265,6 @@ func (r *DefaultRequestor) addHeadersToRequest(headers *map[string]string, req *
 		return
 	}
 	for k, v := range *headers {
-		req.Header.Add(k, v)
+		req.Header.Set(k, v)
 	}
 }
@@ -0,0 +1,121 @@
+package scrobble
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/history"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	listenTypeImport = "import"
+	submissionClient = "spotify-cli"
+	musicService     = "spotify.com"
+)
+
+// LastFMHeader is the header row of the Last.fm scrobble CSV. Timestamps are
+// UTC in RFC 3339 and durations are in seconds.
+var LastFMHeader = []string{"Artist", "Track", "Album", "Timestamp", "Album Artist", "Duration"}
+
+// NewListen converts a play into a listen. Plays without a track name or
+// artist can't be scrobbled and come back as false.
+func NewListen(play history.Play) (Listen, bool) {
+	if play.TrackName == "" || len(play.Artists) == 0 {
+		return Listen{}, false
+	}
+	listen := Listen{
+		ListenedAt: play.PlayedAt.Unix(),
+		TrackMetadata: TrackMetadata{
+			ArtistName:  joinArtists(play.Artists),
+			TrackName:   play.TrackName,
+			ReleaseName: play.AlbumName,
+			AdditionalInfo: AdditionalInfo{
+				DurationMS:       play.DurationMS,
+				MusicService:     musicService,
+				SubmissionClient: submissionClient,
+			},
+		},
+	}
+	info := &listen.TrackMetadata.AdditionalInfo
+	for _, artist := range play.Artists {
+		info.ArtistNames = append(info.ArtistNames, artist.Name)
+		if artist.ID != "" {
+			info.SpotifyArtistIDs = append(info.SpotifyArtistIDs, openURL("artist", artist.ID))
+		}
+	}
+	if play.TrackID != "" {
+		info.SpotifyID = openURL("track", play.TrackID)
+	}
+	if play.AlbumID != "" {
+		info.SpotifyAlbumID = openURL("album", play.AlbumID)
+	}
+	return listen, true
+}
+
+// WriteListenBrainz writes the plays as a ListenBrainz import submission,
+// skipping those that can't be scrobbled
+func WriteListenBrainz(w io.Writer, plays []history.Play) error {
+	submission := Submission{
+		ListenType: listenTypeImport,
+		Payload:    []Listen{},
+	}
+	for _, play := range plays {
+		if listen, ok := NewListen(play); ok {
+			submission.Payload = append(submission.Payload, listen)
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(submission); err != nil {
+		return errors.WithMessage(err, "Failed to encode listens")
+	}
+	return nil
+}
+
+// WriteLastFM writes the plays as Last.fm scrobble CSV, skipping those that
+// can't be scrobbled. The first artist is used as the album artist.
+func WriteLastFM(w io.Writer, plays []history.Play) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(LastFMHeader); err != nil {
+		return errors.WithMessage(err, "Failed to write scrobbles")
+	}
+	for _, play := range plays {
+		if play.TrackName == "" || len(play.Artists) == 0 {
+			continue
+		}
+		record := []string{
+			joinArtists(play.Artists),
+			play.TrackName,
+			play.AlbumName,
+			play.PlayedAt.UTC().Format(time.RFC3339),
+			play.Artists[0].Name,
+			strconv.Itoa(play.DurationMS / 1000),
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.WithMessage(err, "Failed to write scrobbles")
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.WithMessage(err, "Failed to write scrobbles")
+	}
+	return nil
+}
+
+func joinArtists(artists []history.Artist) string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func openURL(resourceType, id string) string {
+	return "https://open.spotify.com/" + resourceType + "/" + id
+}
@@ -0,0 +1,41 @@
+package scrobble
+
+import "time"
+
+// Listen is a single play in the ListenBrainz listen format
+type Listen struct {
+	ListenedAt    int64         `json:"listened_at"`
+	TrackMetadata TrackMetadata `json:"track_metadata"`
+}
+
+// TrackMetadata -
+type TrackMetadata struct {
+	ArtistName     string         `json:"artist_name"`
+	TrackName      string         `json:"track_name"`
+	ReleaseName    string         `json:"release_name,omitempty"`
+	AdditionalInfo AdditionalInfo `json:"additional_info"`
+}
+
+// AdditionalInfo holds the optional fields ListenBrainz uses to link a listen
+// to the track on Spotify
+type AdditionalInfo struct {
+	DurationMS       int      `json:"duration_ms,omitempty"`
+	ArtistNames      []string `json:"artist_names,omitempty"`
+	SpotifyID        string   `json:"spotify_id,omitempty"`
+	SpotifyAlbumID   string   `json:"spotify_album_id,omitempty"`
+	SpotifyArtistIDs []string `json:"spotify_artist_ids,omitempty"`
+	MusicService     string   `json:"music_service,omitempty"`
+	SubmissionClient string   `json:"submission_client,omitempty"`
+}
+
+// Submission is the body of a submit-listens request, which is also what
+// the ListenBrainz export writes so it can be posted as is
+type Submission struct {
+	ListenType string   `json:"listen_type"`
+	Payload    []Listen `json:"payload"`
+}
+
+// State remembers the latest play submitted to each endpoint
+type State struct {
+	Submitted map[string]time.Time `json:"submitted"`
+}
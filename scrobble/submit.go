@@ -0,0 +1,147 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/history"
+	"github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEndpoint is the ListenBrainz API
+const DefaultEndpoint = "https://api.listenbrainz.org"
+
+// maxListensPerRequest is the most listens ListenBrainz accepts in one submission
+const maxListensPerRequest = 1000
+
+// Submitter sends listens to a ListenBrainz compatible API
+type Submitter struct {
+	endpoint  string
+	token     string
+	requestor req.Requestor
+}
+
+// NewSubmitter returns a submitter for the API at endpoint, authenticating with the user token
+func NewSubmitter(endpoint, token string) *Submitter {
+	return &Submitter{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		token:     token,
+		requestor: req.NewRequestor(),
+	}
+}
+
+// Endpoint -
+func (s *Submitter) Endpoint() string {
+	return s.endpoint
+}
+
+// Send submits the listens, splitting them into as many requests as needed
+func (s *Submitter) Send(ctx context.Context, listens []Listen) error {
+	for len(listens) > 0 {
+		batch := listens
+		if len(batch) > maxListensPerRequest {
+			batch = batch[:maxListensPerRequest]
+		}
+		if err := s.requestor.Post(ctx, &req.PostInput{
+			URL: s.endpoint + "/1/submit-listens",
+			Headers: &map[string]string{
+				"Authorization": "Token " + s.token,
+			},
+			JSONBody: Submission{
+				ListenType: listenTypeImport,
+				Payload:    batch,
+			},
+		}); err != nil {
+			return errors.WithMessage(err, "Failed to submit listens")
+		}
+		listens = listens[len(batch):]
+	}
+	return nil
+}
+
+// Submit sends every play in the store newer than the latest one already
+// submitted to the submitter's endpoint, or than since if that is later,
+// and returns how many were sent. The state file at statePath is updated
+// after every request, so an interrupted submit picks up where it stopped.
+func Submit(ctx context.Context, store *history.Store, submitter *Submitter, statePath string, since time.Time, progress func(done, total int)) (int, error) {
+	state, err := loadState(statePath)
+	if err != nil {
+		return 0, err
+	}
+	from := state.Submitted[submitter.endpoint]
+	if since.After(from) {
+		from = since
+	}
+
+	var plays []history.Play
+	if err := store.Read(from, time.Time{}, func(play history.Play) error {
+		// from itself was already submitted
+		if play.PlayedAt.After(from) || from.IsZero() {
+			plays = append(plays, play)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for len(plays) > 0 {
+		batch := plays
+		if len(batch) > maxListensPerRequest {
+			batch = batch[:maxListensPerRequest]
+		}
+		var listens []Listen
+		for _, play := range batch {
+			if listen, ok := NewListen(play); ok {
+				listens = append(listens, listen)
+			}
+		}
+		if err := submitter.Send(ctx, listens); err != nil {
+			return sent, err
+		}
+		sent += len(listens)
+		plays = plays[len(batch):]
+
+		state.Submitted[submitter.endpoint] = batch[len(batch)-1].PlayedAt
+		if err := state.save(statePath); err != nil {
+			return sent, err
+		}
+		progress(sent, sent+len(plays))
+	}
+	return sent, nil
+}
+
+func loadState(statePath string) (*State, error) {
+	state := &State{Submitted: map[string]time.Time{}}
+	data, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read submit state")
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.WithMessage(err, "Failed to unmarshal submit state")
+	}
+	if state.Submitted == nil {
+		state.Submitted = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+func (s *State) save(statePath string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal submit state")
+	}
+	if err := ioutil.WriteFile(statePath, data, 0644); err != nil {
+		return errors.WithMessage(err, "Failed to write submit state")
+	}
+	return nil
+}
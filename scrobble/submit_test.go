@@ -0,0 +1,207 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwseger/spotify-cli/history"
+)
+
+// fakeListenBrainz records the submissions posted to it
+type fakeListenBrainz struct {
+	*httptest.Server
+	mu          sync.Mutex
+	submissions []Submission
+}
+
+func newFakeListenBrainz(t *testing.T) *fakeListenBrainz {
+	fake := &fakeListenBrainz{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/1/submit-listens" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Token secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Token secret")
+		}
+		var submission Submission
+		if err := json.NewDecoder(r.Body).Decode(&submission); err != nil {
+			t.Errorf("Failed to decode submission: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		fake.submissions = append(fake.submissions, submission)
+		fake.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	return fake
+}
+
+func (f *fakeListenBrainz) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sizes []int
+	for _, submission := range f.submissions {
+		sizes = append(sizes, len(submission.Payload))
+	}
+	return sizes
+}
+
+func newTestStore(t *testing.T, dir string, start time.Time, n int) *history.Store {
+	store, err := history.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendPlays(t, store, start, n)
+	return store
+}
+
+func appendPlays(t *testing.T, store *history.Store, start time.Time, n int) {
+	var plays []history.Play
+	for i := 0; i < n; i++ {
+		plays = append(plays, history.Play{
+			PlayedAt:   start.Add(time.Duration(i) * time.Minute),
+			TrackID:    "track",
+			TrackName:  "Lady Lady",
+			DurationMS: 200000,
+			AlbumID:    "album",
+			AlbumName:  "Texas Moon",
+			Artists:    []history.Artist{{ID: "artist", Name: "Khruangbin"}, {Name: "Leon Bridges"}},
+		})
+	}
+	if _, err := store.Append(plays); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSubmit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrobble")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(t, filepath.Join(dir, "history"), start, 2500)
+	statePath := filepath.Join(dir, "state.json")
+	noProgress := func(done, total int) {}
+
+	fake := newFakeListenBrainz(t)
+	defer fake.Close()
+	submitter := NewSubmitter(fake.URL+"/", "secret")
+
+	sent, err := Submit(context.Background(), store, submitter, statePath, time.Time{}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 2500 {
+		t.Errorf("Sent %d listens, want 2500", sent)
+	}
+	if sizes := fake.batchSizes(); !equalInts(sizes, []int{1000, 1000, 500}) {
+		t.Errorf("Batch sizes = %v, want [1000 1000 500]", sizes)
+	}
+
+	submission := fake.submissions[0]
+	if submission.ListenType != "import" {
+		t.Errorf("Listen type = %q, want import", submission.ListenType)
+	}
+	listen := submission.Payload[0]
+	if listen.ListenedAt != start.Unix() {
+		t.Errorf("Listened at = %d, want %d", listen.ListenedAt, start.Unix())
+	}
+	metadata := listen.TrackMetadata
+	if metadata.ArtistName != "Khruangbin, Leon Bridges" || metadata.TrackName != "Lady Lady" || metadata.ReleaseName != "Texas Moon" {
+		t.Errorf("Track metadata = %+v", metadata)
+	}
+	info := metadata.AdditionalInfo
+	if info.SpotifyID != "https://open.spotify.com/track/track" || info.SpotifyAlbumID != "https://open.spotify.com/album/album" ||
+		len(info.SpotifyArtistIDs) != 1 || len(info.ArtistNames) != 2 || info.DurationMS != 200000 ||
+		info.MusicService != "spotify.com" || info.SubmissionClient != "spotify-cli" {
+		t.Errorf("Additional info = %+v", info)
+	}
+	last := fake.submissions[2].Payload[499]
+	if want := start.Add(2499 * time.Minute).Unix(); last.ListenedAt != want {
+		t.Errorf("Last listened at = %d, want %d", last.ListenedAt, want)
+	}
+
+	// A second run has nothing new to send
+	sent, err = Submit(context.Background(), store, submitter, statePath, time.Time{}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 0 || len(fake.batchSizes()) != 3 {
+		t.Errorf("Second run sent %d listens in batches %v, want none", sent, fake.batchSizes()[3:])
+	}
+
+	// Only plays after the last submitted one go out
+	appendPlays(t, store, start.Add(3000*time.Minute), 3)
+	sent, err = Submit(context.Background(), store, submitter, statePath, time.Time{}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes := fake.batchSizes(); sent != 3 || !equalInts(sizes, []int{1000, 1000, 500, 3}) {
+		t.Errorf("Third run sent %d listens in batches %v, want 3 in one more", sent, sizes)
+	}
+
+	// State is kept per endpoint, so another one gets everything
+	other := newFakeListenBrainz(t)
+	defer other.Close()
+	sent, err = Submit(context.Background(), store, NewSubmitter(other.URL, "secret"), statePath, time.Time{}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes := other.batchSizes(); sent != 2503 || !equalInts(sizes, []int{1000, 1000, 503}) {
+		t.Errorf("Other endpoint got %d listens in batches %v, want 2503 in [1000 1000 503]", sent, sizes)
+	}
+}
+
+func TestSubmitSkipsUnscrobblablePlays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrobble")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := history.Open(filepath.Join(dir, "history"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := store.Append([]history.Play{
+		{PlayedAt: start, TrackName: "No artist"},
+		{PlayedAt: start.Add(time.Minute), TrackName: "Maria También", Artists: []history.Artist{{Name: "Khruangbin"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := newFakeListenBrainz(t)
+	defer fake.Close()
+	sent, err := Submit(context.Background(), store, NewSubmitter(fake.URL, "secret"), filepath.Join(dir, "state.json"), time.Time{}, func(done, total int) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(fake.submissions) != 1 || fake.submissions[0].Payload[0].TrackMetadata.TrackName != "Maria También" {
+		t.Errorf("Sent %d listens: %+v", sent, fake.submissions)
+	}
+}
@@ -0,0 +1,102 @@
+package smart
+
+import "github.com/cwseger/spotify-cli/spotify"
+
+// Definition describes a smart playlist: where its tracks come from, which of
+// them to keep, how to order them and which playlist to write them to.
+type Definition struct {
+	Name    string    `yaml:"name"`
+	Target  Target    `yaml:"target"`
+	Sources []Source  `yaml:"sources"`
+	Filters Filters   `yaml:"filters"`
+	Sort    []SortKey `yaml:"sort"`
+	Limit   int       `yaml:"limit"`
+}
+
+// Target is the playlist the result is written to. Playlist takes an ID, URI
+// or link; without it the user's own playlist called Name is used, and
+// created if it doesn't exist yet.
+type Target struct {
+	Playlist    string `yaml:"playlist"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Public      bool   `yaml:"public"`
+}
+
+// Source -
+type Source struct {
+	// Type is one of saved, playlist, artist or recommendations
+	Type string `yaml:"type"`
+	// Playlist is the ID, URI or link of a playlist source
+	Playlist string `yaml:"playlist"`
+	// Artist is the name, ID, URI or link of an artist source, whose albums and singles are used
+	Artist string `yaml:"artist"`
+	// SeedArtists, SeedTracks, SeedGenres and Limit configure a recommendations source
+	SeedArtists []string `yaml:"seed_artists"`
+	SeedTracks  []string `yaml:"seed_tracks"`
+	SeedGenres  []string `yaml:"seed_genres"`
+	Limit       int      `yaml:"limit"`
+}
+
+// Range is an inclusive range, either end may be left open
+type Range struct {
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// DurationRange is a Range of durations such as "2m30s"
+type DurationRange struct {
+	Min string `yaml:"min"`
+	Max string `yaml:"max"`
+}
+
+// Filters -
+type Filters struct {
+	Year       *Range         `yaml:"year"`
+	Popularity *Range         `yaml:"popularity"`
+	Duration   *DurationRange `yaml:"duration"`
+	Explicit   *bool          `yaml:"explicit"`
+	// Genres keeps tracks by an artist with a genre containing any of these
+	Genres []string `yaml:"genres"`
+	// ExcludeGenres drops tracks by an artist with a genre containing any of these
+	ExcludeGenres []string `yaml:"exclude_genres"`
+	// Features maps audio feature names such as energy or tempo to the range they must fall in
+	Features map[string]Range `yaml:"features"`
+}
+
+// SortKey -
+type SortKey struct {
+	By    string `yaml:"by"`
+	Order string `yaml:"order"`
+}
+
+// Candidate is a track picked up from a source along with everything the
+// filters and sort keys look at.
+type Candidate struct {
+	Track    spotify.Track
+	AddedAt  string
+	Genres   []string
+	Features *spotify.AudioFeatures
+}
+
+// Diff -
+type Diff struct {
+	Added     []spotify.Track
+	Removed   []spotify.Track
+	Reordered bool
+}
+
+// Unchanged reports whether applying the diff would leave the playlist as is
+func (d *Diff) Unchanged() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && !d.Reordered
+}
+
+// SyncOutput -
+type SyncOutput struct {
+	PlaylistID   string
+	PlaylistName string
+	Created      bool
+	Applied      bool
+	Tracks       []spotify.Track
+	Diff         Diff
+}
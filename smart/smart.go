@@ -0,0 +1,556 @@
+package smart
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/spotify"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var features = map[string]func(f *spotify.AudioFeatures) float64{
+	"danceability":     func(f *spotify.AudioFeatures) float64 { return f.Danceability },
+	"energy":           func(f *spotify.AudioFeatures) float64 { return f.Energy },
+	"key":              func(f *spotify.AudioFeatures) float64 { return float64(f.Key) },
+	"loudness":         func(f *spotify.AudioFeatures) float64 { return f.Loudness },
+	"mode":             func(f *spotify.AudioFeatures) float64 { return float64(f.Mode) },
+	"speechiness":      func(f *spotify.AudioFeatures) float64 { return f.Speechiness },
+	"acousticness":     func(f *spotify.AudioFeatures) float64 { return f.Acousticness },
+	"instrumentalness": func(f *spotify.AudioFeatures) float64 { return f.Instrumentalness },
+	"liveness":         func(f *spotify.AudioFeatures) float64 { return f.Liveness },
+	"valence":          func(f *spotify.AudioFeatures) float64 { return f.Valence },
+	"tempo":            func(f *spotify.AudioFeatures) float64 { return f.Tempo },
+	"time_signature":   func(f *spotify.AudioFeatures) float64 { return float64(f.TimeSignature) },
+}
+
+var sortKeys = map[string]bool{
+	"name":         true,
+	"artist":       true,
+	"album":        true,
+	"popularity":   true,
+	"release_date": true,
+	"duration":     true,
+	"added_at":     true,
+	"random":       true,
+}
+
+// Load reads a definition from a YAML or JSON file
+func Load(path string) (*Definition, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read definition")
+	}
+	var def Definition
+	if err := yaml.UnmarshalStrict(data, &def); err != nil {
+		return nil, errors.WithMessage(err, "Failed to parse definition")
+	}
+	if err := def.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "Invalid definition")
+	}
+	return &def, nil
+}
+
+// Validate -
+func (d *Definition) Validate() error {
+	if d.Target.Playlist == "" && d.Target.Name == "" && d.Name == "" {
+		return errors.New("target needs a playlist or a name")
+	}
+	if len(d.Sources) == 0 {
+		return errors.New("at least one source is required")
+	}
+	for i, source := range d.Sources {
+		switch source.Type {
+		case "saved":
+		case "playlist":
+			if source.Playlist == "" {
+				return errors.Errorf("source %d: playlist is required", i+1)
+			}
+		case "artist":
+			if source.Artist == "" {
+				return errors.Errorf("source %d: artist is required", i+1)
+			}
+		case "recommendations":
+			seeds := len(source.SeedArtists) + len(source.SeedTracks) + len(source.SeedGenres)
+			if seeds == 0 || seeds > 5 {
+				return errors.Errorf("source %d: recommendations take between 1 and 5 seeds", i+1)
+			}
+		default:
+			return errors.Errorf("source %d: unknown type %q", i+1, source.Type)
+		}
+	}
+	if d.Filters.Duration != nil {
+		if _, _, err := d.Filters.Duration.bounds(); err != nil {
+			return err
+		}
+	}
+	for name := range d.Filters.Features {
+		if _, ok := features[name]; !ok {
+			return errors.Errorf("unknown audio feature %q", name)
+		}
+	}
+	for _, key := range d.Sort {
+		if _, ok := features[key.By]; !ok && !sortKeys[key.By] {
+			return errors.Errorf("unknown sort key %q", key.By)
+		}
+		if key.Order != "" && key.Order != "asc" && key.Order != "desc" {
+			return errors.Errorf("sort order must be asc or desc, got %q", key.Order)
+		}
+	}
+	if d.Limit < 0 {
+		return errors.New("limit can't be negative")
+	}
+	return nil
+}
+
+// Evaluate collects the tracks from every source, drops duplicates and
+// anything the filters reject, then sorts and limits what is left.
+func Evaluate(ctx context.Context, client spotify.Client, def *Definition) ([]spotify.Track, error) {
+	candidates, err := collect(ctx, client, def.Sources)
+	if err != nil {
+		return nil, err
+	}
+	if err := enrich(ctx, client, def, candidates); err != nil {
+		return nil, err
+	}
+
+	var kept []*Candidate
+	for _, candidate := range candidates {
+		keep, err := def.Filters.match(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			kept = append(kept, candidate)
+		}
+	}
+
+	sortCandidates(kept, def.Sort)
+	if def.Limit > 0 && len(kept) > def.Limit {
+		kept = kept[:def.Limit]
+	}
+
+	tracks := make([]spotify.Track, len(kept))
+	for i, candidate := range kept {
+		tracks[i] = candidate.Track
+	}
+	return tracks, nil
+}
+
+// Sync evaluates def and makes its target playlist match the result. Nothing
+// is written when dryRun is set or the playlist is already up to date, so
+// running it repeatedly is safe.
+func Sync(ctx context.Context, client spotify.Client, def *Definition, dryRun bool) (*SyncOutput, error) {
+	tracks, err := Evaluate(ctx, client, def)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to evaluate smart playlist")
+	}
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := &SyncOutput{
+		PlaylistName: def.Target.Name,
+		Tracks:       tracks,
+	}
+	if output.PlaylistName == "" {
+		output.PlaylistName = def.Name
+	}
+
+	if def.Target.Playlist != "" {
+		output.PlaylistID = def.Target.Playlist
+		if id, ok := spotify.ParseID(def.Target.Playlist, "playlist"); ok {
+			output.PlaylistID = id
+		}
+	} else {
+		playlists, err := client.GetCurrentUserPlaylists(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, playlist := range playlists.Items {
+			if playlist.Owner.ID == user.ID && playlist.Name == output.PlaylistName {
+				output.PlaylistID = playlist.ID
+				break
+			}
+		}
+	}
+
+	var current []spotify.Track
+	if output.PlaylistID != "" {
+		items, err := client.GetPlaylistTracks(ctx, output.PlaylistID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items.Items {
+			if item.Track != nil {
+				current = append(current, *item.Track)
+			}
+		}
+	}
+	output.Diff = diff(current, tracks)
+
+	if dryRun || (output.PlaylistID != "" && output.Diff.Unchanged()) {
+		return output, nil
+	}
+
+	if output.PlaylistID == "" {
+		playlist, err := client.CreatePlaylist(ctx, user.ID, &spotify.CreatePlaylistInput{
+			Name:        output.PlaylistName,
+			Description: def.Target.Description,
+			Public:      def.Target.Public,
+		})
+		if err != nil {
+			return nil, err
+		}
+		output.PlaylistID = playlist.ID
+		output.Created = true
+	}
+
+	uris := make([]string, len(tracks))
+	for i, track := range tracks {
+		uris[i] = track.URI
+	}
+	if err := client.ReplacePlaylistTracks(ctx, output.PlaylistID, uris); err != nil {
+		return nil, err
+	}
+	output.Applied = true
+	return output, nil
+}
+
+func collect(ctx context.Context, client spotify.Client, sources []Source) ([]*Candidate, error) {
+	var candidates []*Candidate
+	seen := map[string]bool{}
+	add := func(track spotify.Track, addedAt string) {
+		if track.ID == "" || track.IsLocal || seen[track.ID] {
+			return
+		}
+		seen[track.ID] = true
+		candidates = append(candidates, &Candidate{
+			Track:   track,
+			AddedAt: addedAt,
+		})
+	}
+
+	for _, source := range sources {
+		switch source.Type {
+		case "saved":
+			out, err := client.GetSavedTracks(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range out.Items {
+				add(item.Track, item.AddedAt)
+			}
+		case "playlist":
+			out, err := client.GetPlaylistTracks(ctx, source.Playlist)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range out.Items {
+				if item.Track != nil {
+					add(*item.Track, item.AddedAt)
+				}
+			}
+		case "artist":
+			out, err := client.GetArtistTracks(ctx, source.Artist)
+			if err != nil {
+				return nil, err
+			}
+			for _, track := range out.Tracks {
+				add(track, "")
+			}
+		case "recommendations":
+			input, err := recommendationsInput(ctx, client, source)
+			if err != nil {
+				return nil, err
+			}
+			out, err := client.GetRecommendations(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			for _, track := range out.Tracks {
+				add(track, "")
+			}
+		}
+	}
+	return candidates, nil
+}
+
+func recommendationsInput(ctx context.Context, client spotify.Client, source Source) (*spotify.GetRecommendationsInput, error) {
+	input := &spotify.GetRecommendationsInput{
+		SeedGenres: source.SeedGenres,
+		Limit:      source.Limit,
+	}
+	for _, artist := range source.SeedArtists {
+		if id, ok := spotify.ParseID(artist, "artist"); ok {
+			input.SeedArtists = append(input.SeedArtists, id)
+			continue
+		}
+		out, err := client.GetArtist(ctx, artist)
+		if err != nil {
+			return nil, err
+		}
+		if len(out.Inner.Artists) == 0 {
+			return nil, errors.Errorf("No artist found for %q", artist)
+		}
+		input.SeedArtists = append(input.SeedArtists, out.Inner.Artists[0].ID)
+	}
+	for _, track := range source.SeedTracks {
+		if id, ok := spotify.ParseID(track, "track"); ok {
+			track = id
+		}
+		input.SeedTracks = append(input.SeedTracks, track)
+	}
+	return input, nil
+}
+
+// enrich looks up genres and audio features, but only when something in the
+// definition needs them since both cost extra requests.
+func enrich(ctx context.Context, client spotify.Client, def *Definition, candidates []*Candidate) error {
+	needsFeatures := len(def.Filters.Features) > 0
+	for _, key := range def.Sort {
+		if _, ok := features[key.By]; ok {
+			needsFeatures = true
+		}
+	}
+	needsGenres := len(def.Filters.Genres) > 0 || len(def.Filters.ExcludeGenres) > 0
+
+	if needsGenres {
+		var artistIDs []string
+		seen := map[string]bool{}
+		for _, candidate := range candidates {
+			for _, artist := range candidate.Track.Artists {
+				if artist.ID != "" && !seen[artist.ID] {
+					seen[artist.ID] = true
+					artistIDs = append(artistIDs, artist.ID)
+				}
+			}
+		}
+		out, err := client.GetSeveralArtists(ctx, artistIDs)
+		if err != nil {
+			return err
+		}
+		genres := map[string][]string{}
+		for _, artist := range out.Artists {
+			if artist != nil {
+				genres[artist.ID] = artist.Genres
+			}
+		}
+		for _, candidate := range candidates {
+			for _, artist := range candidate.Track.Artists {
+				candidate.Genres = append(candidate.Genres, genres[artist.ID]...)
+			}
+		}
+	}
+
+	if needsFeatures {
+		trackIDs := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			trackIDs[i] = candidate.Track.ID
+		}
+		out, err := client.GetAudioFeatures(ctx, trackIDs)
+		if err != nil {
+			return err
+		}
+		for i, f := range out.AudioFeatures {
+			if i < len(candidates) {
+				candidates[i].Features = f
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Filters) match(c *Candidate) (bool, error) {
+	track := c.Track
+	if f.Explicit != nil && track.Explicit != *f.Explicit {
+		return false, nil
+	}
+	if f.Popularity != nil && !f.Popularity.contains(float64(track.Popularity)) {
+		return false, nil
+	}
+	if f.Year != nil {
+		year, ok := releaseYear(track.Album.ReleaseDate)
+		if !ok || !f.Year.contains(float64(year)) {
+			return false, nil
+		}
+	}
+	if f.Duration != nil {
+		min, max, err := f.Duration.bounds()
+		if err != nil {
+			return false, err
+		}
+		duration := time.Duration(track.DurationMS) * time.Millisecond
+		if (min > 0 && duration < min) || (max > 0 && duration > max) {
+			return false, nil
+		}
+	}
+	if len(f.Genres) > 0 && !matchesGenre(c.Genres, f.Genres) {
+		return false, nil
+	}
+	if len(f.ExcludeGenres) > 0 && matchesGenre(c.Genres, f.ExcludeGenres) {
+		return false, nil
+	}
+	for name, r := range f.Features {
+		if c.Features == nil || !r.contains(features[name](c.Features)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *Range) contains(v float64) bool {
+	if r.Min != nil && v < *r.Min {
+		return false
+	}
+	if r.Max != nil && v > *r.Max {
+		return false
+	}
+	return true
+}
+
+func (r *DurationRange) bounds() (time.Duration, time.Duration, error) {
+	var min, max time.Duration
+	var err error
+	if r.Min != "" {
+		if min, err = time.ParseDuration(r.Min); err != nil {
+			return 0, 0, errors.WithMessage(err, "Invalid minimum duration")
+		}
+	}
+	if r.Max != "" {
+		if max, err = time.ParseDuration(r.Max); err != nil {
+			return 0, 0, errors.WithMessage(err, "Invalid maximum duration")
+		}
+	}
+	return min, max, nil
+}
+
+func matchesGenre(genres []string, terms []string) bool {
+	for _, genre := range genres {
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(genre), strings.ToLower(term)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func releaseYear(releaseDate string) (int, bool) {
+	if len(releaseDate) < 4 {
+		return 0, false
+	}
+	year, err := strconv.Atoi(releaseDate[:4])
+	return year, err == nil
+}
+
+func sortCandidates(candidates []*Candidate, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	random := map[string]int{}
+	for _, c := range candidates {
+		random[c.Track.ID] = rand.Int()
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compare(candidates[i], candidates[j], key.By, random)
+			if cmp == 0 {
+				continue
+			}
+			if key.Order == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+func compare(a, b *Candidate, by string, random map[string]int) int {
+	switch by {
+	case "name":
+		return strings.Compare(strings.ToLower(a.Track.Name), strings.ToLower(b.Track.Name))
+	case "artist":
+		return strings.Compare(strings.ToLower(firstArtist(a.Track)), strings.ToLower(firstArtist(b.Track)))
+	case "album":
+		return strings.Compare(strings.ToLower(a.Track.Album.Name), strings.ToLower(b.Track.Album.Name))
+	case "popularity":
+		return compareFloats(float64(a.Track.Popularity), float64(b.Track.Popularity))
+	case "release_date":
+		return strings.Compare(a.Track.Album.ReleaseDate, b.Track.Album.ReleaseDate)
+	case "duration":
+		return compareFloats(float64(a.Track.DurationMS), float64(b.Track.DurationMS))
+	case "added_at":
+		return strings.Compare(a.AddedAt, b.AddedAt)
+	case "random":
+		return compareFloats(float64(random[a.Track.ID]), float64(random[b.Track.ID]))
+	}
+
+	feature := features[by]
+	switch {
+	case a.Features == nil && b.Features == nil:
+		return 0
+	case a.Features == nil:
+		return 1
+	case b.Features == nil:
+		return -1
+	}
+	return compareFloats(feature(a.Features), feature(b.Features))
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func firstArtist(track spotify.Track) string {
+	if len(track.Artists) == 0 {
+		return ""
+	}
+	return track.Artists[0].Name
+}
+
+func diff(current, desired []spotify.Track) Diff {
+	var d Diff
+	inCurrent := map[string]bool{}
+	for _, track := range current {
+		inCurrent[track.URI] = true
+	}
+	inDesired := map[string]bool{}
+	for _, track := range desired {
+		inDesired[track.URI] = true
+		if !inCurrent[track.URI] {
+			d.Added = append(d.Added, track)
+		}
+	}
+	for _, track := range current {
+		if !inDesired[track.URI] {
+			d.Removed = append(d.Removed, track)
+		}
+	}
+
+	// Duplicates in the current playlist also need a rewrite, so they count as reordering
+	if len(d.Added) == 0 && len(d.Removed) == 0 {
+		if len(current) != len(desired) {
+			d.Reordered = true
+		}
+		for i := 0; i < len(current) && !d.Reordered; i++ {
+			d.Reordered = current[i].URI != desired[i].URI
+		}
+	}
+	return d
+}
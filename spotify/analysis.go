@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+var pitchClasses = []string{"C", "C♯/D♭", "D", "D♯/E♭", "E", "F", "F♯/G♭", "G", "G♯/A♭", "A", "A♯/B♭", "B"}
+
+// GetAudioAnalysis gets the low level analysis of a track's structure and
+// rhythm. The response is kept in Raw as well since it has more than is modelled.
+func (c *DefaultClient) GetAudioAnalysis(ctx context.Context, track string) (*AudioAnalysis, error) {
+	trackID, err := c.ResolveID(ctx, track, "track")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for track")
+	}
+
+	var raw json.RawMessage
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/audio-analysis/{trackID}",
+		Slugs: &map[string]string{
+			"{trackID}": trackID,
+		},
+		Destination: &raw,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get audio analysis")
+	}
+
+	var output AudioAnalysis
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return nil, errors.WithMessage(err, "Failed to unmarshal audio analysis")
+	}
+	output.Raw = raw
+	return &output, nil
+}
+
+// KeyName turns a pitch class and mode as used in audio features and
+// analysis into musical notation, such as "F♯/G♭ minor". Keys that weren't
+// detected are -1 and come back as "unknown".
+func KeyName(key, mode int) string {
+	if key < 0 || key >= len(pitchClasses) {
+		return "unknown"
+	}
+	if mode == 1 {
+		return pitchClasses[key] + " major"
+	}
+	return pitchClasses[key] + " minor"
+}
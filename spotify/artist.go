@@ -0,0 +1,131 @@
+package spotify
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// AlbumGroups are all the ways an album can belong to an artist, in the order a discography lists them
+var AlbumGroups = []string{"album", "single", "compilation", "appears_on"}
+
+// GetArtistTopTracks gets the artist's ten most popular tracks in a market. Spotify
+// needs a market for this, so an empty one falls back to the client's, and
+// the request fails when the client doesn't have one either.
+func (c *DefaultClient) GetArtistTopTracks(ctx context.Context, artist, market string) (*GetArtistTopTracksOutput, error) {
+	artistID, err := c.ResolveID(ctx, artist, "artist")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
+	}
+	var output GetArtistTopTracksOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/artists/{artistID}/top-tracks",
+		Slugs: &map[string]string{
+			"{artistID}": artistID,
+		},
+		QueryParams: marketParams(market),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get artist's top tracks")
+	}
+	return &output, nil
+}
+
+// GetRelatedArtists gets artists that listeners of the artist also listen to
+func (c *DefaultClient) GetRelatedArtists(ctx context.Context, artist string) (*GetRelatedArtistsOutput, error) {
+	artistID, err := c.ResolveID(ctx, artist, "artist")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
+	}
+	var output GetRelatedArtistsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/artists/{artistID}/related-artists",
+		Slugs: &map[string]string{
+			"{artistID}": artistID,
+		},
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get related artists")
+	}
+	return &output, nil
+}
+
+// GetArtistDiscography returns every release in every album group, oldest
+// first. Releases are often listed once per region; those copies share a
+// name, group and track count and only the first is kept. An empty market
+// falls back to the client's, and only when that is empty too are the
+// releases of every country listed.
+func (c *DefaultClient) GetArtistDiscography(ctx context.Context, artist, market string) (*GetArtistDiscographyOutput, error) {
+	artistID, err := c.ResolveID(ctx, artist, "artist")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
+	}
+	albums, err := c.getArtistAlbums(ctx, artistID, strings.Join(AlbumGroups, ","), market)
+	if err != nil {
+		return nil, err
+	}
+
+	var output GetArtistDiscographyOutput
+	seen := map[string]bool{}
+	for _, album := range albums {
+		key := strings.ToLower(strings.TrimSpace(album.Name)) + "|" + album.AlbumGroup + "|" + strconv.Itoa(album.TotalTracks)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		output.Albums = append(output.Albums, album)
+	}
+
+	group := map[string]int{}
+	for i, name := range AlbumGroups {
+		group[name] = i
+	}
+	sort.SliceStable(output.Albums, func(i, j int) bool {
+		a, b := output.Albums[i], output.Albums[j]
+		if a.ReleaseDate != b.ReleaseDate {
+			return a.ReleaseDate < b.ReleaseDate
+		}
+		if a.AlbumGroup != b.AlbumGroup {
+			return group[a.AlbumGroup] < group[b.AlbumGroup]
+		}
+		return a.Name < b.Name
+	})
+	return &output, nil
+}
+
+// getArtistAlbums pages through the artist's albums in the comma separated groups
+func (c *DefaultClient) getArtistAlbums(ctx context.Context, artistID, groups, market string) ([]Album, error) {
+	queryParams := map[string]string{
+		"include_groups": groups,
+		"limit":          "50",
+	}
+	if market != "" {
+		queryParams["market"] = market
+	}
+
+	var pages []*GetArtistAlbumOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/artists/{artistID}/albums",
+		Slugs: &map[string]string{
+			"{artistID}": artistID,
+		},
+		QueryParams: &queryParams,
+	}, func() pager {
+		page := &GetArtistAlbumOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get artist's albums")
+	}
+
+	var albums []Album
+	for _, page := range pages {
+		albums = append(albums, page.Albums...)
+	}
+	return albums, nil
+}
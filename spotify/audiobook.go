@@ -0,0 +1,71 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetAudiobook gets an audiobook's details. Audiobooks are only sold in some
+// countries, so without a user token a market is needed to find most of them.
+func (c *DefaultClient) GetAudiobook(ctx context.Context, audiobook, market string) (*Audiobook, error) {
+	audiobookID, err := c.ResolveID(ctx, audiobook, "audiobook")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for audiobook")
+	}
+	var output Audiobook
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/audiobooks/{audiobookID}",
+		Slugs: &map[string]string{
+			"{audiobookID}": audiobookID,
+		},
+		QueryParams: marketParams(market),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get audiobook")
+	}
+	return &output, nil
+}
+
+// GetAudiobookChapters gets a page of up to 50 of an audiobook's chapters, in order
+func (c *DefaultClient) GetAudiobookChapters(ctx context.Context, audiobookID, market string, limit, offset int) (*GetAudiobookChaptersOutput, error) {
+	queryParams := marketParams(market)
+	(*queryParams)["limit"] = strconv.Itoa(limit)
+	(*queryParams)["offset"] = strconv.Itoa(offset)
+	var output GetAudiobookChaptersOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/audiobooks/{audiobookID}/chapters",
+		Slugs: &map[string]string{
+			"{audiobookID}": audiobookID,
+		},
+		QueryParams: queryParams,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get audiobook chapters")
+	}
+	return &output, nil
+}
+
+// GetChapter gets a chapter and the audiobook it belongs to. Chapters can't
+// be searched for, so they have to be given by ID, URI or link.
+func (c *DefaultClient) GetChapter(ctx context.Context, chapter, market string) (*Chapter, error) {
+	chapterID, ok := ParseID(chapter, "chapter")
+	if !ok {
+		return nil, errors.Errorf("Chapters can only be given by ID, URI or link, not %q", chapter)
+	}
+	var output Chapter
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/chapters/{chapterID}",
+		Slugs: &map[string]string{
+			"{chapterID}": chapterID,
+		},
+		QueryParams: marketParams(market),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get chapter")
+	}
+	return &output, nil
+}
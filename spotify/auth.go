@@ -0,0 +1,284 @@
+package spotify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	authorizeURL       = "https://accounts.spotify.com/authorize"
+	tokenURL           = "https://accounts.spotify.com/api/token"
+	defaultRedirectURI = "http://127.0.0.1:8888/callback"
+)
+
+// scopes are the permissions requested when a user logs in
+var scopes = []string{
+	"user-read-private",
+	"user-read-email",
+	"user-library-read",
+	"user-library-modify",
+	"user-read-playback-position",
+	"user-follow-read",
+	"user-follow-modify",
+	"user-top-read",
+	"user-read-recently-played",
+	"user-read-currently-playing",
+	"playlist-read-private",
+	"playlist-read-collaborative",
+	"playlist-modify-public",
+	"playlist-modify-private",
+}
+
+// ErrNotLoggedIn is returned when a command needs a user token but nobody has logged in yet
+var ErrNotLoggedIn = errors.New("Not logged in, run `spotify-cli login` first")
+
+// ErrMissingPermission is returned when the saved user token was granted
+// before a permission the request needs was added to the login
+var ErrMissingPermission = errors.New("Your login doesn't allow this yet, run `spotify-cli login` again")
+
+// NewUserClient creates a client that acts on behalf of the user that last
+// logged in with Login. The token is refreshed and saved as it expires.
+func NewUserClient() (*DefaultClient, error) {
+	tokenPath, err := TokenPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(tokenPath)
+	if os.IsNotExist(err) {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read token file")
+	}
+
+	var token GetTokenOutput
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, errors.WithMessage(err, "Failed to unmarshal token file")
+	}
+	if token.RefreshToken == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	return &DefaultClient{
+		authToken: &token,
+		tokenPath: tokenPath,
+		requestor: req.NewRequestor(),
+	}, nil
+}
+
+// TokenPath is where the user token is stored between runs
+func TokenPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "token.json"), nil
+}
+
+// ConfigDir is the directory the cli keeps its files in
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithMessage(err, "Failed to find user config directory")
+	}
+	return filepath.Join(dir, "spotify-cli"), nil
+}
+
+// Login runs the authorization code flow: it serves the redirect URI locally,
+// hands the URL the user has to visit to openURL and waits for Spotify to
+// redirect back with a code, which is exchanged for a token and saved.
+func Login(ctx context.Context, openURL func(string)) (*User, error) {
+	redirectURI := os.Getenv("REDIRECT_URI")
+	if redirectURI == "" {
+		redirectURI = defaultRedirectURI
+	}
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to parse redirect uri")
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to listen on redirect uri")
+	}
+
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "State mismatch", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			fmt.Fprintln(w, "Login failed, you can close this window.")
+			select {
+			case failures <- errors.New("Authorization was denied: " + query.Get("error")):
+			default:
+			}
+		default:
+			fmt.Fprintln(w, "Logged in, you can close this window.")
+			select {
+			case codes <- query.Get("code"):
+			default:
+			}
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	openURL(authorizeURL + "?" + url.Values{
+		"client_id":     {os.Getenv("CLIENT_ID")},
+		"response_type": {"code"},
+		"redirect_uri":  {redirectURI},
+		"scope":         {strings.Join(scopes, " ")},
+		"state":         {state},
+	}.Encode())
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-failures:
+		return nil, err
+	case code = <-codes:
+	}
+
+	token, err := requestToken(ctx, os.Getenv("CLIENT_ID"), os.Getenv("CLIENT_SECRET"), map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": redirectURI,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to exchange code for token")
+	}
+
+	tokenPath, err := TokenPath()
+	if err != nil {
+		return nil, err
+	}
+	client := &DefaultClient{
+		authToken: token,
+		tokenPath: tokenPath,
+		requestor: req.NewRequestor(),
+	}
+	if err := client.saveToken(); err != nil {
+		return nil, err
+	}
+	return client.GetCurrentUser(ctx)
+}
+
+// Logout removes the saved user token
+func Logout() error {
+	tokenPath, err := TokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "Failed to remove token file")
+	}
+	return nil
+}
+
+// authHeaders returns the headers for an authorized request, renewing the
+// access token first if it is about to expire.
+func (c *DefaultClient) authHeaders(ctx context.Context) (*map[string]string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if !c.authToken.ExpiresAt.IsZero() && time.Now().Add(time.Minute).After(c.authToken.ExpiresAt) {
+		if err := c.renewToken(ctx); err != nil {
+			return nil, errors.WithMessage(err, "Failed to renew access token")
+		}
+	}
+	return &map[string]string{
+		"Authorization": "Bearer " + c.authToken.AccessToken,
+	}, nil
+}
+
+func (c *DefaultClient) renewToken(ctx context.Context) error {
+	if c.tokenPath == "" {
+		token, err := getAccessToken(os.Getenv("CLIENT_ID"), os.Getenv("CLIENT_SECRET"))
+		if err != nil {
+			return err
+		}
+		c.authToken = token
+		return nil
+	}
+
+	token, err := requestToken(ctx, os.Getenv("CLIENT_ID"), os.Getenv("CLIENT_SECRET"), map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": c.authToken.RefreshToken,
+	})
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = c.authToken.RefreshToken
+	}
+	c.authToken = token
+	return c.saveToken()
+}
+
+func (c *DefaultClient) saveToken() error {
+	data, err := json.MarshalIndent(c.authToken, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal token")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.tokenPath), 0700); err != nil {
+		return errors.WithMessage(err, "Failed to create config directory")
+	}
+	if err := ioutil.WriteFile(c.tokenPath, data, 0600); err != nil {
+		return errors.WithMessage(err, "Failed to write token file")
+	}
+	return nil
+}
+
+func requestToken(ctx context.Context, clientID, clientSecret string, body map[string]string) (*GetTokenOutput, error) {
+	data := []byte(fmt.Sprintf("%s:%s", clientID, clientSecret))
+	headers := &map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString(data),
+	}
+	var output GetTokenOutput
+	if err := req.NewRequestor().Post(ctx, &req.PostInput{
+		URL:         tokenURL,
+		Headers:     headers,
+		Body:        &body,
+		Destination: &output,
+	}); err != nil {
+		return nil, err
+	}
+	output.ExpiresAt = time.Now().Add(time.Duration(output.ExpiresIn) * time.Second)
+	return &output, nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithMessage(err, "Failed to generate state")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,162 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetCategoryList gets a page of the categories used to tag items in Spotify
+func (c *DefaultClient) GetCategoryList(ctx context.Context, input *BrowseInput) (*GetCategoriesOutput, error) {
+	var output GetCategoriesOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/browse/categories",
+		QueryParams: browseParams(input),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get category list")
+	}
+	return &output, nil
+}
+
+// GetAllCategories pages through every category from input's offset on
+func (c *DefaultClient) GetAllCategories(ctx context.Context, input *BrowseInput) (*GetCategoriesOutput, error) {
+	var pages []*GetCategoriesOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/browse/categories",
+		QueryParams: browseParams(allPages(input)),
+	}, func() pager {
+		page := &GetCategoriesOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get category list")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Inner.Items = append(output.Inner.Items, page.Inner.Items...)
+	}
+	output.Inner.Next = ""
+	return output, nil
+}
+
+// GetCategory gets a single category. Only the country and locale of input are used.
+func (c *DefaultClient) GetCategory(ctx context.Context, categoryID string, input *BrowseInput) (*Category, error) {
+	var output Category
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/browse/categories/{categoryID}",
+		Slugs: &map[string]string{
+			"{categoryID}": categoryID,
+		},
+		QueryParams: browseParams(&BrowseInput{Country: input.Country, Locale: input.Locale}),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get category")
+	}
+	return &output, nil
+}
+
+// GetCategoryPlaylists gets a page of the playlists tagged with a category
+func (c *DefaultClient) GetCategoryPlaylists(ctx context.Context, categoryID string, input *BrowseInput) (*GetCategoryPlaylistsOutput, error) {
+	var output GetCategoryPlaylistsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/browse/categories/{categoryID}/playlists",
+		Slugs: &map[string]string{
+			"{categoryID}": categoryID,
+		},
+		QueryParams: browseParams(input),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get category's playlists")
+	}
+	return &output, nil
+}
+
+// GetAllCategoryPlaylists pages through every playlist tagged with a category from input's offset on
+func (c *DefaultClient) GetAllCategoryPlaylists(ctx context.Context, categoryID string, input *BrowseInput) (*GetCategoryPlaylistsOutput, error) {
+	var pages []*GetCategoryPlaylistsOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/browse/categories/{categoryID}/playlists",
+		Slugs: &map[string]string{
+			"{categoryID}": categoryID,
+		},
+		QueryParams: browseParams(allPages(input)),
+	}, func() pager {
+		page := &GetCategoryPlaylistsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get category's playlists")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Inner.Items = append(output.Inner.Items, page.Inner.Items...)
+	}
+	output.Inner.Next = ""
+	return output, nil
+}
+
+// GetFeaturedPlaylists gets a page of the playlists Spotify features, along
+// with the message it shows above them
+func (c *DefaultClient) GetFeaturedPlaylists(ctx context.Context, input *BrowseInput) (*GetFeaturedPlaylistsOutput, error) {
+	var output GetFeaturedPlaylistsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/browse/featured-playlists",
+		QueryParams: browseParams(input),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get featured playlists")
+	}
+	return &output, nil
+}
+
+// GetAllFeaturedPlaylists pages through every featured playlist from input's offset on
+func (c *DefaultClient) GetAllFeaturedPlaylists(ctx context.Context, input *BrowseInput) (*GetFeaturedPlaylistsOutput, error) {
+	var pages []*GetFeaturedPlaylistsOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/browse/featured-playlists",
+		QueryParams: browseParams(allPages(input)),
+	}, func() pager {
+		page := &GetFeaturedPlaylistsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get featured playlists")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Inner.Items = append(output.Inner.Items, page.Inner.Items...)
+	}
+	output.Inner.Next = ""
+	return output, nil
+}
+
+// allPages asks for pages as big as they come, starting from input's offset
+func allPages(input *BrowseInput) *BrowseInput {
+	all := *input
+	all.Limit = 50
+	return &all
+}
+
+func browseParams(input *BrowseInput) *map[string]string {
+	queryParams := map[string]string{}
+	if input.Country != "" {
+		queryParams["country"] = input.Country
+	}
+	if input.Locale != "" {
+		queryParams["locale"] = input.Locale
+	}
+	if input.Limit > 0 {
+		queryParams["limit"] = strconv.Itoa(input.Limit)
+	}
+	if input.Offset > 0 {
+		queryParams["offset"] = strconv.Itoa(input.Offset)
+	}
+	return &queryParams
+}
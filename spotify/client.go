@@ -2,9 +2,10 @@ package spotify
 
 import (
 	"context"
-	"encoding/base64"
-	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	req "github.com/cwseger/spotify-cli/req"
 
@@ -15,20 +16,80 @@ import (
 type Client interface {
 	GetArtist(ctx context.Context, artist string) (*GetArtistOutput, error)
 	GetArtistAlbums(ctx context.Context, artist string) (*GetArtistAlbumOutput, error)
-	GetCategoryList(ctx context.Context, limit string) (*GetCategoriesOutput, error)
-	GetCategoryPlaylists(ctx context.Context, categoryID string) (*GetCategoryPlaylistsOutput, error)
-	GetRecommendationsByArtist(ctx context.Context, artist string) (*GetRecommendationsByArtistOutput, error)
+	GetCategoryList(ctx context.Context, input *BrowseInput) (*GetCategoriesOutput, error)
+	GetAllCategories(ctx context.Context, input *BrowseInput) (*GetCategoriesOutput, error)
+	GetCategory(ctx context.Context, categoryID string, input *BrowseInput) (*Category, error)
+	GetCategoryPlaylists(ctx context.Context, categoryID string, input *BrowseInput) (*GetCategoryPlaylistsOutput, error)
+	GetAllCategoryPlaylists(ctx context.Context, categoryID string, input *BrowseInput) (*GetCategoryPlaylistsOutput, error)
+	GetFeaturedPlaylists(ctx context.Context, input *BrowseInput) (*GetFeaturedPlaylistsOutput, error)
+	GetAllFeaturedPlaylists(ctx context.Context, input *BrowseInput) (*GetFeaturedPlaylistsOutput, error)
 	GetNewReleases(ctx context.Context) (*GetNewReleasesOutput, error)
+	GetAllNewReleases(ctx context.Context, country string) (*GetNewReleasesOutput, error)
 	GetAlbum(ctx context.Context, album string) (*GetAlbumOutput, error)
+	GetTrack(ctx context.Context, track string) (*Track, error)
 	GetAlbumTracks(ctx context.Context, album string) (*GetAlbumTracksOutput, error)
+	GetArtistTracks(ctx context.Context, artist string) (*GetArtistTracksOutput, error)
+	GetArtistTopTracks(ctx context.Context, artist, market string) (*GetArtistTopTracksOutput, error)
+	GetRelatedArtists(ctx context.Context, artist string) (*GetRelatedArtistsOutput, error)
+	GetArtistDiscography(ctx context.Context, artist, market string) (*GetArtistDiscographyOutput, error)
+	GetRecommendations(ctx context.Context, input *GetRecommendationsInput) (*GetRecommendationsOutput, error)
+	GetGenreSeeds(ctx context.Context) (*GetGenreSeedsOutput, error)
+	GetSeveralTracks(ctx context.Context, ids []string) (*GetSeveralTracksOutput, error)
+	GetSeveralArtists(ctx context.Context, ids []string) (*GetSeveralArtistsOutput, error)
+	GetSeveralAlbums(ctx context.Context, ids []string) (*GetSeveralAlbumsOutput, error)
+	GetSeveralEpisodes(ctx context.Context, ids []string) (*GetSeveralEpisodesOutput, error)
+	GetSeveralShows(ctx context.Context, ids []string) (*GetSeveralShowsOutput, error)
+	SearchTracks(ctx context.Context, query string, limit int) ([]Track, error)
+	SearchTracksByISRC(ctx context.Context, isrc string) ([]Track, error)
+	SearchAlbumsByUPC(ctx context.Context, upc string) ([]Album, error)
+	GetAudioFeatures(ctx context.Context, ids []string) (*GetAudioFeaturesOutput, error)
+	GetAudioAnalysis(ctx context.Context, track string) (*AudioAnalysis, error)
+	GetCurrentUser(ctx context.Context) (*User, error)
+	GetShow(ctx context.Context, show, market string) (*Show, error)
+	GetShowEpisodes(ctx context.Context, showID, market string, limit, offset int) (*GetShowEpisodesOutput, error)
+	GetEpisode(ctx context.Context, episode, market string) (*Episode, error)
+	GetAudiobook(ctx context.Context, audiobook, market string) (*Audiobook, error)
+	GetAudiobookChapters(ctx context.Context, audiobookID, market string, limit, offset int) (*GetAudiobookChaptersOutput, error)
+	GetChapter(ctx context.Context, chapter, market string) (*Chapter, error)
+	GetSavedTracks(ctx context.Context) (*GetSavedTracksOutput, error)
+	GetSavedAlbums(ctx context.Context) (*GetSavedAlbumsOutput, error)
+	GetSavedShows(ctx context.Context) (*GetSavedShowsOutput, error)
+	GetSavedEpisodes(ctx context.Context) (*GetSavedEpisodesOutput, error)
+	GetSavedAudiobooks(ctx context.Context) (*GetSavedAudiobooksOutput, error)
+	SaveToLibrary(ctx context.Context, libraryType LibraryType, ids []string) error
+	RemoveFromLibrary(ctx context.Context, libraryType LibraryType, ids []string) error
+	CheckLibrary(ctx context.Context, libraryType LibraryType, ids []string) ([]bool, error)
+	GetFollowedArtists(ctx context.Context) (*GetFollowedArtistsOutput, error)
+	Follow(ctx context.Context, followType string, ids []string) error
+	FollowPlaylist(ctx context.Context, playlistID string, public bool) error
+	GetFollowedArtistsPage(ctx context.Context, after string, limit int) (*GetFollowedArtistsOutput, error)
+	Unfollow(ctx context.Context, followType string, ids []string) error
+	CheckFollowing(ctx context.Context, followType string, ids []string) ([]bool, error)
+	UnfollowPlaylist(ctx context.Context, playlistID string) error
+	CheckFollowingPlaylist(ctx context.Context, playlistID string) (bool, error)
+	GetTopArtists(ctx context.Context, timeRange string, limit int) (*GetTopArtistsOutput, error)
+	GetTopTracks(ctx context.Context, timeRange string, limit int) (*GetTopTracksOutput, error)
+	GetRecentlyPlayed(ctx context.Context, after time.Time) (*GetRecentlyPlayedOutput, error)
+	GetCurrentlyPlaying(ctx context.Context) (*CurrentlyPlaying, error)
+	ResolveID(ctx context.Context, resource string, resourceType string) (string, error)
+	GetCurrentUserPlaylists(ctx context.Context) (*GetCurrentUserPlaylistsOutput, error)
+	GetPlaylist(ctx context.Context, playlist string) (*Playlist, error)
+	GetPlaylistTracks(ctx context.Context, playlist string) (*GetPlaylistTracksOutput, error)
+	CreatePlaylist(ctx context.Context, userID string, input *CreatePlaylistInput) (*Playlist, error)
+	ReplacePlaylistTracks(ctx context.Context, playlistID string, uris []string) error
+	AddPlaylistTracks(ctx context.Context, playlistID string, uris []string) error
 }
 
 var _ Client = &DefaultClient{}
 
 // DefaultClient -
 type DefaultClient struct {
-	authToken *GetTokenOutput
-	requestor req.Requestor
+	authToken   *GetTokenOutput
+	tokenPath   string
+	tokenMu     sync.Mutex
+	market      string
+	concurrency int
+	requestor   req.Requestor
 }
 
 // NewClient -
@@ -51,14 +112,10 @@ func (c *DefaultClient) GetArtist(ctx context.Context, artist string) (*GetArtis
 		"limit": "1",
 		"type":  "artist",
 	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
-	}
 	var output GetArtistOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
+	if err := c.get(ctx, &req.GetInput{
 		URL:         "https://api.spotify.com/v1/search",
 		QueryParams: queryParams,
-		Headers:     headers,
 		Destination: &output,
 	}); err != nil {
 		return nil, errors.WithMessage(err, "Failed to get artist")
@@ -68,21 +125,17 @@ func (c *DefaultClient) GetArtist(ctx context.Context, artist string) (*GetArtis
 
 // GetArtistAlbums -
 func (c *DefaultClient) GetArtistAlbums(ctx context.Context, artist string) (*GetArtistAlbumOutput, error) {
-	var getArtistSearchOutput GetArtistSearchOutput
-	if err := c.getSpotifyIDForResource(ctx, artist, "artist", &getArtistSearchOutput); err != nil {
+	artistID, err := c.ResolveID(ctx, artist, "artist")
+	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
 	}
 	slugs := &map[string]string{
-		"{artistID}": getArtistSearchOutput.Inner.Items[0].ID,
-	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+		"{artistID}": artistID,
 	}
 	var output GetArtistAlbumOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
+	if err := c.get(ctx, &req.GetInput{
 		URL:         "https://api.spotify.com/v1/artists/{artistID}/albums",
 		Slugs:       slugs,
-		Headers:     headers,
 		Destination: &output,
 	}); err != nil {
 		return nil, errors.WithMessage(err, "Failed to get artist's albums")
@@ -90,120 +143,66 @@ func (c *DefaultClient) GetArtistAlbums(ctx context.Context, artist string) (*Ge
 	return &output, nil
 }
 
-// GetCategoryList -
-func (c *DefaultClient) GetCategoryList(ctx context.Context, limit string) (*GetCategoriesOutput, error) {
+// GetNewReleases -
+func (c *DefaultClient) GetNewReleases(ctx context.Context) (*GetNewReleasesOutput, error) {
 	queryParams := &map[string]string{
-		"limit": limit,
-	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+		"limit": "50",
 	}
-	var output GetCategoriesOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
-		URL:         "https://api.spotify.com/v1/browse/categories",
+	var output GetNewReleasesOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/browse/new-releases",
 		QueryParams: queryParams,
-		Headers:     headers,
 		Destination: &output,
 	}); err != nil {
-		return nil, errors.WithMessage(err, "Failed to get category list")
+		return nil, errors.WithMessage(err, "Failed to get new releases")
 	}
-	return &output, nil
-}
 
-// GetCategoryPlaylists -
-func (c *DefaultClient) GetCategoryPlaylists(ctx context.Context, categoryID string) (*GetCategoryPlaylistsOutput, error) {
-	slugs := &map[string]string{
-		"{categoryID}": categoryID,
-	}
-	queryParams := &map[string]string{
-		"limit": "5",
-	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
-	}
-	var output GetCategoryPlaylistsOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
-		URL:         "https://api.spotify.com/v1/browse/categories/{categoryID}/playlists",
-		Slugs:       slugs,
-		QueryParams: queryParams,
-		Headers:     headers,
-		Destination: &output,
-	}); err != nil {
-		return nil, errors.WithMessage(err, "Failed to get category's playlists")
-	}
 	return &output, nil
 }
 
-// GetRecommendationsByArtist -
-func (c *DefaultClient) GetRecommendationsByArtist(ctx context.Context, artist string) (*GetRecommendationsByArtistOutput, error) {
-	var getArtistSearchOutput GetArtistSearchOutput
-	if err := c.getSpotifyIDForResource(ctx, artist, "artist", &getArtistSearchOutput); err != nil {
-		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
-	}
-	queryParams := &map[string]string{
-		"seed_artists": getArtistSearchOutput.Inner.Items[0].ID,
-		"limit":        "10",
-	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
-	}
-	var output GetRecommendationsByArtistOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
-		URL:         "https://api.spotify.com/v1/recommendations",
-		QueryParams: queryParams,
-		Headers:     headers,
-		Destination: &output,
-	}); err != nil {
-		return nil, errors.WithMessage(err, "Failed to get recommendations by artist")
-	}
-	return &output, nil
-}
-
-// GetNewReleases -
-func (c *DefaultClient) GetNewReleases(ctx context.Context) (*GetNewReleasesOutput, error) {
-	queryParams := &map[string]string{
+// GetAllNewReleases pages through every album Spotify features as new in a
+// country, or everywhere if country is empty
+func (c *DefaultClient) GetAllNewReleases(ctx context.Context, country string) (*GetNewReleasesOutput, error) {
+	queryParams := map[string]string{
 		"limit": "50",
 	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+	if country != "" {
+		queryParams["country"] = country
 	}
-	var output GetNewReleasesOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
+	var pages []*GetNewReleasesOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
 		URL:         "https://api.spotify.com/v1/browse/new-releases",
-		QueryParams: queryParams,
-		Headers:     headers,
-		Destination: &output,
+		QueryParams: &queryParams,
+	}, func() pager {
+		page := &GetNewReleasesOutput{}
+		pages = append(pages, page)
+		return page
 	}); err != nil {
 		return nil, errors.WithMessage(err, "Failed to get new releases")
 	}
 
-	return &output, nil
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Inner.Items = append(output.Inner.Items, page.Inner.Items...)
+	}
+	output.Inner.Next = ""
+	return output, nil
 }
 
 // GetAlbum -
 func (c *DefaultClient) GetAlbum(ctx context.Context, album string) (*GetAlbumOutput, error) {
-	var getAlbumSearchOutput GetAlbumSearchOutput
-	if err := c.getSpotifyIDForResource(ctx, album, "album", &getAlbumSearchOutput); err != nil {
+	albumID, err := c.ResolveID(ctx, album, "album")
+	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to get spotify id for album")
 	}
-	queryParams := &map[string]string{
-		"q":     album,
-		"limit": "1",
-		"type":  "album",
-	}
 	slugs := &map[string]string{
-		"{albumID}": getAlbumSearchOutput.Inner.Items[0].ID,
-	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+		"{albumID}": albumID,
 	}
 
 	var output GetAlbumOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
+	if err := c.get(ctx, &req.GetInput{
 		URL:         "https://api.spotify.com/v1/albums/{albumID}",
 		Slugs:       slugs,
-		QueryParams: queryParams,
-		Headers:     headers,
 		Destination: &output,
 	}); err != nil {
 		return nil, errors.WithMessage(err, "Failed to get album")
@@ -211,73 +210,181 @@ func (c *DefaultClient) GetAlbum(ctx context.Context, album string) (*GetAlbumOu
 	return &output, nil
 }
 
-// GetAlbumTracks -
+// GetTrack gets a single track. With a market set, the track may come back
+// relinked to another version, with the one asked for in LinkedFrom.
+func (c *DefaultClient) GetTrack(ctx context.Context, track string) (*Track, error) {
+	trackID, err := c.ResolveID(ctx, track, "track")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for track")
+	}
+	var output Track
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/tracks/{trackID}",
+		Slugs: &map[string]string{
+			"{trackID}": trackID,
+		},
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get track")
+	}
+	return &output, nil
+}
+
+// GetAlbumTracks returns every track on the album
 func (c *DefaultClient) GetAlbumTracks(ctx context.Context, album string) (*GetAlbumTracksOutput, error) {
-	var getAlbumSearchOutput GetAlbumSearchOutput
-	if err := c.getSpotifyIDForResource(ctx, album, "album", &getAlbumSearchOutput); err != nil {
+	albumID, err := c.ResolveID(ctx, album, "album")
+	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to get spotify id for album")
 	}
-	queryParams := &map[string]string{
-		"limit": "50",
+
+	var pages []*GetAlbumTracksOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/albums/{albumID}/tracks",
+		Slugs: &map[string]string{
+			"{albumID}": albumID,
+		},
+		QueryParams: &map[string]string{
+			"limit": "50",
+		},
+	}, func() pager {
+		page := &GetAlbumTracksOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get album tracks")
 	}
-	slugs := &map[string]string{
-		"{albumID}": getAlbumSearchOutput.Inner.Items[0].ID,
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Tracks = append(output.Tracks, page.Tracks...)
 	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+	return output, nil
+}
+
+// GetArtistTracks returns every track on the artist's albums and singles
+func (c *DefaultClient) GetArtistTracks(ctx context.Context, artist string) (*GetArtistTracksOutput, error) {
+	artistID, err := c.ResolveID(ctx, artist, "artist")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for artist")
 	}
 
-	var output GetAlbumTracksOutput
-	if err := c.requestor.Get(ctx, &req.GetInput{
-		URL:         "https://api.spotify.com/v1/albums/{albumID}/tracks",
-		Slugs:       slugs,
-		QueryParams: queryParams,
-		Headers:     headers,
-		Destination: &output,
-	}); err != nil {
-		return nil, errors.WithMessage(err, "Failed to get album tracks")
+	albums, err := c.getArtistAlbums(ctx, artistID, "album,single", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var trackIDs []string
+	for _, album := range albums {
+		albumTracks, err := c.GetAlbumTracks(ctx, album.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range albumTracks.Tracks {
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+
+	tracks, err := c.GetSeveralTracks(ctx, trackIDs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get artist's tracks")
+	}
+	var output GetArtistTracksOutput
+	for _, track := range tracks.Tracks {
+		if track != nil {
+			output.Tracks = append(output.Tracks, *track)
+		}
 	}
 	return &output, nil
 }
 
-func (c *DefaultClient) getSpotifyIDForResource(ctx context.Context, resource string, resourceType string, output interface{}) error {
-	queryParams := &map[string]string{
-		"q":    resource,
-		"type": resourceType,
+// GetAudioFeatures looks up audio features 100 tracks at a time. Tracks without features are nil.
+func (c *DefaultClient) GetAudioFeatures(ctx context.Context, ids []string) (*GetAudioFeaturesOutput, error) {
+	var output GetAudioFeaturesOutput
+	for _, ids := range Chunk(ids, 100) {
+		var page GetAudioFeaturesOutput
+		if err := c.get(ctx, &req.GetInput{
+			URL: "https://api.spotify.com/v1/audio-features",
+			QueryParams: &map[string]string{
+				"ids": strings.Join(ids, ","),
+			},
+			Destination: &page,
+		}); err != nil {
+			return nil, errors.WithMessage(err, "Failed to get audio features")
+		}
+		output.AudioFeatures = append(output.AudioFeatures, page.AudioFeatures...)
+	}
+	return &output, nil
+}
+
+// ResolveID returns the ID of a resource given as an ID, URI or link,
+// falling back to the top search result for anything else.
+func (c *DefaultClient) ResolveID(ctx context.Context, resource string, resourceType string) (string, error) {
+	if id, ok := ParseID(resource, resourceType); ok {
+		return id, nil
 	}
-	headers := &map[string]string{
-		"Authorization": "Bearer " + c.authToken.AccessToken,
+	queryParams := &map[string]string{
+		"q":     resource,
+		"type":  resourceType,
+		"limit": "1",
 	}
-	if err := c.requestor.Get(ctx, &req.GetInput{
+	output := map[string]GetSearchInner{}
+	if err := c.get(ctx, &req.GetInput{
 		URL:         "https://api.spotify.com/v1/search",
 		QueryParams: queryParams,
-		Headers:     headers,
-		Destination: output,
+		Destination: &output,
 	}); err != nil {
-		return errors.WithMessage(err, "Failed to search for spotify id")
+		return "", errors.WithMessage(err, "Failed to search for spotify id")
+	}
+	items := output[resourceType+"s"].Items
+	if len(items) == 0 {
+		return "", errors.Errorf("No %s found for %q", resourceType, resource)
 	}
-	return nil
+	return items[0].ID, nil
 }
 
-func getAccessToken(clientID, clientSecret string) (*GetTokenOutput, error) {
-	data := []byte(fmt.Sprintf("%s:%s", clientID, clientSecret))
-	str := base64.StdEncoding.EncodeToString(data)
+func (c *DefaultClient) get(ctx context.Context, input *req.GetInput) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	input.Headers = headers
+	c.applyMarket(input)
+	return c.requestor.Get(ctx, input)
+}
 
-	headers := &map[string]string{
-		"Authorization": "Basic " + str,
-		"Content-Type":  "application/x-www-form-urlencoded",
+func (c *DefaultClient) post(ctx context.Context, input *req.PostInput) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
 	}
-	body := &map[string]string{
-		"grant_type": "client_credentials",
+	input.Headers = headers
+	return c.requestor.Post(ctx, input)
+}
+
+func (c *DefaultClient) put(ctx context.Context, input *req.PutInput) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
 	}
-	var output GetTokenOutput
-	if err := req.NewRequestor().Post(context.Background(), &req.PostInput{
-		URL:         "https://accounts.spotify.com/api/token",
-		Headers:     headers,
-		Body:        body,
-		Destination: &output,
-	}); err != nil {
+	input.Headers = headers
+	return c.requestor.Put(ctx, input)
+}
+
+func (c *DefaultClient) delete(ctx context.Context, input *req.DeleteInput) error {
+	headers, err := c.authHeaders(ctx)
+	if err != nil {
+		return err
+	}
+	input.Headers = headers
+	return c.requestor.Delete(ctx, input)
+}
+
+func getAccessToken(clientID, clientSecret string) (*GetTokenOutput, error) {
+	output, err := requestToken(context.Background(), clientID, clientSecret, map[string]string{
+		"grant_type": "client_credentials",
+	})
+	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to get access token")
 	}
-	return &output, nil
+	return output, nil
 }
@@ -0,0 +1,64 @@
+package spotify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Config holds the defaults set with the config command
+type Config struct {
+	// Market is used when --market isn't given
+	Market string `json:"market,omitempty"`
+}
+
+// ConfigPath is where the config is stored
+func ConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// LoadConfig reads the config, which is empty until something is set
+func LoadConfig() (*Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to read config file")
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.WithMessage(err, "Failed to parse config file")
+	}
+	return &config, nil
+}
+
+// Save writes the config
+func (c *Config) Save() error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "Failed to marshal config")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WithMessage(err, "Failed to create config directory")
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.WithMessage(err, "Failed to write config file")
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package spotify
+
+import "time"
+
+// ParseReleaseDate parses the release date of an album, show or episode. When
+// Spotify only knows the month or year, as precision says, it is the first
+// day of that month or year.
+func ParseReleaseDate(date, precision string) (time.Time, bool) {
+	layout := "2006-01-02"
+	switch precision {
+	case "year":
+		layout = "2006"
+	case "month":
+		layout = "2006-01"
+	}
+	day, err := time.Parse(layout, date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
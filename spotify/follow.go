@@ -0,0 +1,156 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetFollowedArtists returns every artist the user follows
+func (c *DefaultClient) GetFollowedArtists(ctx context.Context) (*GetFollowedArtistsOutput, error) {
+	var pages []*GetFollowedArtistsOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/me/following",
+		QueryParams: &map[string]string{
+			"type":  "artist",
+			"limit": "50",
+		},
+	}, func() pager {
+		page := &GetFollowedArtistsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get followed artists")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Inner.Items = append(output.Inner.Items, page.Inner.Items...)
+	}
+	output.Inner.Next = ""
+	return output, nil
+}
+
+// Follow follows artists or users, 50 at a time. followType is artist or user.
+func (c *DefaultClient) Follow(ctx context.Context, followType string, ids []string) error {
+	for _, ids := range Chunk(ids, 50) {
+		if err := c.put(ctx, &req.PutInput{
+			URL: "https://api.spotify.com/v1/me/following",
+			QueryParams: &map[string]string{
+				"type": followType,
+				"ids":  strings.Join(ids, ","),
+			},
+		}); err != nil {
+			return errors.WithMessagef(err, "Failed to follow %ss", followType)
+		}
+	}
+	return nil
+}
+
+// FollowPlaylist -
+func (c *DefaultClient) FollowPlaylist(ctx context.Context, playlistID string, public bool) error {
+	if err := c.put(ctx, &req.PutInput{
+		URL: "https://api.spotify.com/v1/playlists/{playlistID}/followers",
+		Slugs: &map[string]string{
+			"{playlistID}": playlistID,
+		},
+		JSONBody: map[string]bool{
+			"public": public,
+		},
+	}); err != nil {
+		return errors.WithMessage(err, "Failed to follow playlist")
+	}
+	return nil
+}
+
+// GetFollowedArtistsPage returns a single page of followed artists, starting
+// after the artist ID in after. An empty after starts at the beginning.
+func (c *DefaultClient) GetFollowedArtistsPage(ctx context.Context, after string, limit int) (*GetFollowedArtistsOutput, error) {
+	queryParams := map[string]string{
+		"type":  "artist",
+		"limit": strconv.Itoa(limit),
+	}
+	if after != "" {
+		queryParams["after"] = after
+	}
+	var output GetFollowedArtistsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/me/following",
+		QueryParams: &queryParams,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get followed artists")
+	}
+	return &output, nil
+}
+
+// Unfollow unfollows artists or users, 50 at a time. followType is artist or user.
+func (c *DefaultClient) Unfollow(ctx context.Context, followType string, ids []string) error {
+	for _, ids := range Chunk(ids, 50) {
+		if err := c.delete(ctx, &req.DeleteInput{
+			URL: "https://api.spotify.com/v1/me/following",
+			QueryParams: &map[string]string{
+				"type": followType,
+				"ids":  strings.Join(ids, ","),
+			},
+		}); err != nil {
+			return errors.WithMessagef(err, "Failed to unfollow %ss", followType)
+		}
+	}
+	return nil
+}
+
+// CheckFollowing reports, in order, whether the user follows each artist or user
+func (c *DefaultClient) CheckFollowing(ctx context.Context, followType string, ids []string) ([]bool, error) {
+	var output []bool
+	for _, ids := range Chunk(ids, 50) {
+		var page []bool
+		if err := c.get(ctx, &req.GetInput{
+			URL: "https://api.spotify.com/v1/me/following/contains",
+			QueryParams: &map[string]string{
+				"type": followType,
+				"ids":  strings.Join(ids, ","),
+			},
+			Destination: &page,
+		}); err != nil {
+			return nil, errors.WithMessagef(err, "Failed to check followed %ss", followType)
+		}
+		if len(page) != len(ids) {
+			return nil, errors.Errorf("Checked %d followed %ss but got %d answers", len(ids), followType, len(page))
+		}
+		output = append(output, page...)
+	}
+	return output, nil
+}
+
+// UnfollowPlaylist -
+func (c *DefaultClient) UnfollowPlaylist(ctx context.Context, playlistID string) error {
+	if err := c.delete(ctx, &req.DeleteInput{
+		URL: "https://api.spotify.com/v1/playlists/{playlistID}/followers",
+		Slugs: &map[string]string{
+			"{playlistID}": playlistID,
+		},
+	}); err != nil {
+		return errors.WithMessage(err, "Failed to unfollow playlist")
+	}
+	return nil
+}
+
+// CheckFollowingPlaylist reports whether the user follows the playlist
+func (c *DefaultClient) CheckFollowingPlaylist(ctx context.Context, playlistID string) (bool, error) {
+	var output []bool
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/playlists/{playlistID}/followers/contains",
+		Slugs: &map[string]string{
+			"{playlistID}": playlistID,
+		},
+		Destination: &output,
+	}); err != nil {
+		return false, errors.WithMessage(err, "Failed to check followed playlist")
+	}
+	return len(output) > 0 && output[0], nil
+}
@@ -0,0 +1,65 @@
+package spotify
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ParseID extracts the Spotify ID from a bare ID, a URI ("spotify:track:<id>")
+// or an open.spotify.com link of the given resource type. The second return
+// value is false when input is none of those, in which case it is most likely
+// a name that still has to be searched for.
+func ParseID(input, resourceType string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if isID(input) {
+		return input, true
+	}
+
+	if prefix := "spotify:" + resourceType + ":"; strings.HasPrefix(input, prefix) {
+		return strings.TrimPrefix(input, prefix), true
+	}
+
+	u, err := url.Parse(input)
+	if err != nil || u.Host != "open.spotify.com" {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == resourceType {
+			return parts[i+1], true
+		}
+	}
+	return "", false
+}
+
+// isID reports whether s looks like a Spotify ID, which is always 22 base62 characters
+func isID(s string) bool {
+	if len(s) != 22 {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// URI builds the Spotify URI for the given resource type and ID
+func URI(resourceType, id string) string {
+	return "spotify:" + resourceType + ":" + id
+}
+
+// Chunk splits ids into slices of at most size, for endpoints that take a
+// limited number of IDs at once
+func Chunk(ids []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(ids) {
+		chunks = append(chunks, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
@@ -0,0 +1,212 @@
+package spotify
+
+import (
+	"context"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// LibraryType is a kind of item that can be saved to the user's library
+type LibraryType string
+
+// LibraryType values
+const (
+	LibraryTracks     LibraryType = "tracks"
+	LibraryAlbums     LibraryType = "albums"
+	LibraryShows      LibraryType = "shows"
+	LibraryEpisodes   LibraryType = "episodes"
+	LibraryAudiobooks LibraryType = "audiobooks"
+)
+
+// LibraryTypes lists every LibraryType
+var LibraryTypes = []LibraryType{LibraryTracks, LibraryAlbums, LibraryShows, LibraryEpisodes, LibraryAudiobooks}
+
+// libraryBatchSizes are the most IDs each library endpoint accepts in one request
+var libraryBatchSizes = map[LibraryType]int{
+	LibraryTracks:     50,
+	LibraryAlbums:     20,
+	LibraryShows:      50,
+	LibraryEpisodes:   50,
+	LibraryAudiobooks: 50,
+}
+
+// ParseLibraryType -
+func ParseLibraryType(s string) (LibraryType, error) {
+	for _, t := range LibraryTypes {
+		if s == string(t) || s == t.ResourceType() {
+			return t, nil
+		}
+	}
+	return "", errors.Errorf("Unknown library type %q", s)
+}
+
+// ResourceType is the singular name used for the type in URIs and searches
+func (t LibraryType) ResourceType() string {
+	return strings.TrimSuffix(string(t), "s")
+}
+
+// GetSavedTracks returns every track in the user's library
+func (c *DefaultClient) GetSavedTracks(ctx context.Context) (*GetSavedTracksOutput, error) {
+	var pages []*GetSavedTracksOutput
+	if err := c.getAllPages(ctx, c.savedItemsInput(LibraryTracks), func() pager {
+		page := &GetSavedTracksOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get saved tracks")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// GetSavedAlbums returns every album in the user's library
+func (c *DefaultClient) GetSavedAlbums(ctx context.Context) (*GetSavedAlbumsOutput, error) {
+	var pages []*GetSavedAlbumsOutput
+	if err := c.getAllPages(ctx, c.savedItemsInput(LibraryAlbums), func() pager {
+		page := &GetSavedAlbumsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get saved albums")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// GetSavedShows returns every show the user follows
+func (c *DefaultClient) GetSavedShows(ctx context.Context) (*GetSavedShowsOutput, error) {
+	var pages []*GetSavedShowsOutput
+	if err := c.getAllPages(ctx, c.savedItemsInput(LibraryShows), func() pager {
+		page := &GetSavedShowsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get saved shows")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// GetSavedEpisodes returns every episode in the user's library
+func (c *DefaultClient) GetSavedEpisodes(ctx context.Context) (*GetSavedEpisodesOutput, error) {
+	var pages []*GetSavedEpisodesOutput
+	if err := c.getAllPages(ctx, c.savedItemsInput(LibraryEpisodes), func() pager {
+		page := &GetSavedEpisodesOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get saved episodes")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// GetSavedAudiobooks returns every audiobook in the user's library
+func (c *DefaultClient) GetSavedAudiobooks(ctx context.Context) (*GetSavedAudiobooksOutput, error) {
+	var pages []*GetSavedAudiobooksOutput
+	if err := c.getAllPages(ctx, c.savedItemsInput(LibraryAudiobooks), func() pager {
+		page := &GetSavedAudiobooksOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get saved audiobooks")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// SaveToLibrary saves the items to the user's library, batching as needed
+func (c *DefaultClient) SaveToLibrary(ctx context.Context, libraryType LibraryType, ids []string) error {
+	for _, ids := range Chunk(ids, libraryBatchSizes[libraryType]) {
+		if err := c.put(ctx, &req.PutInput{
+			URL: "https://api.spotify.com/v1/me/{type}",
+			Slugs: &map[string]string{
+				"{type}": string(libraryType),
+			},
+			QueryParams: &map[string]string{
+				"ids": strings.Join(ids, ","),
+			},
+		}); err != nil {
+			return errors.WithMessagef(err, "Failed to save %s", libraryType)
+		}
+	}
+	return nil
+}
+
+// RemoveFromLibrary removes the items from the user's library, batching as needed
+func (c *DefaultClient) RemoveFromLibrary(ctx context.Context, libraryType LibraryType, ids []string) error {
+	for _, ids := range Chunk(ids, libraryBatchSizes[libraryType]) {
+		if err := c.delete(ctx, &req.DeleteInput{
+			URL: "https://api.spotify.com/v1/me/{type}",
+			Slugs: &map[string]string{
+				"{type}": string(libraryType),
+			},
+			QueryParams: &map[string]string{
+				"ids": strings.Join(ids, ","),
+			},
+		}); err != nil {
+			return errors.WithMessagef(err, "Failed to remove %s", libraryType)
+		}
+	}
+	return nil
+}
+
+// CheckLibrary reports, in order, whether each item is in the user's library
+func (c *DefaultClient) CheckLibrary(ctx context.Context, libraryType LibraryType, ids []string) ([]bool, error) {
+	var output []bool
+	for _, ids := range Chunk(ids, libraryBatchSizes[libraryType]) {
+		var page []bool
+		if err := c.get(ctx, &req.GetInput{
+			URL: "https://api.spotify.com/v1/me/{type}/contains",
+			Slugs: &map[string]string{
+				"{type}": string(libraryType),
+			},
+			QueryParams: &map[string]string{
+				"ids": strings.Join(ids, ","),
+			},
+			Destination: &page,
+		}); err != nil {
+			return nil, errors.WithMessagef(err, "Failed to check saved %s", libraryType)
+		}
+		if len(page) != len(ids) {
+			return nil, errors.Errorf("Checked %d saved %s but got %d answers", len(ids), libraryType, len(page))
+		}
+		output = append(output, page...)
+	}
+	return output, nil
+}
+
+func (c *DefaultClient) savedItemsInput(libraryType LibraryType) *req.GetInput {
+	return &req.GetInput{
+		URL: "https://api.spotify.com/v1/me/{type}",
+		Slugs: &map[string]string{
+			"{type}": string(libraryType),
+		},
+		QueryParams: &map[string]string{
+			"limit": "50",
+		},
+	}
+}
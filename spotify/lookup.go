@@ -0,0 +1,118 @@
+package spotify
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+	upcPattern  = regexp.MustCompile(`^[0-9]{12,14}$`)
+)
+
+// NormalizeISRC uppercases an ISRC and drops the dashes and spaces it's often
+// written with, as in US-UM7-17-03861
+func NormalizeISRC(isrc string) (string, error) {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isrc))
+	if !isrcPattern.MatchString(code) {
+		return "", errors.Errorf("%q isn't an ISRC, which is two letters, three letters or digits and seven digits", isrc)
+	}
+	return code, nil
+}
+
+// NormalizeUPC drops the dashes and spaces from a UPC or EAN barcode
+func NormalizeUPC(upc string) (string, error) {
+	code := strings.NewReplacer("-", "", " ", "").Replace(upc)
+	if !upcPattern.MatchString(code) {
+		return "", errors.Errorf("%q isn't a UPC or EAN, which is 12 to 14 digits", upc)
+	}
+	return code, nil
+}
+
+// SearchTracks gets the first limit tracks that match a search query, which
+// can use field filters such as track:, artist: and album:
+func (c *DefaultClient) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	var output SearchTracksOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/search",
+		QueryParams: &map[string]string{
+			"q":     query,
+			"type":  "track",
+			"limit": strconv.Itoa(limit),
+		},
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to search for tracks")
+	}
+	return output.Inner.Items, nil
+}
+
+// SearchTracksByISRC finds every track with an ISRC. The same recording can be
+// on several releases, such as the original, a remaster and compilations.
+func (c *DefaultClient) SearchTracksByISRC(ctx context.Context, isrc string) ([]Track, error) {
+	var pages []*SearchTracksOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/search",
+		QueryParams: &map[string]string{
+			"q":     "isrc:" + isrc,
+			"type":  "track",
+			"limit": "50",
+		},
+	}, func() pager {
+		page := &SearchTracksOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to search for isrc")
+	}
+
+	var tracks []Track
+	for _, page := range pages {
+		tracks = append(tracks, page.Inner.Items...)
+	}
+	return tracks, nil
+}
+
+// SearchAlbumsByUPC finds every album with a UPC. Search only returns
+// simplified albums, so the full albums are looked up to get their codes and labels.
+func (c *DefaultClient) SearchAlbumsByUPC(ctx context.Context, upc string) ([]Album, error) {
+	var pages []*SearchAlbumsOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/search",
+		QueryParams: &map[string]string{
+			"q":     "upc:" + upc,
+			"type":  "album",
+			"limit": "50",
+		},
+	}, func() pager {
+		page := &SearchAlbumsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to search for upc")
+	}
+
+	var ids []string
+	for _, page := range pages {
+		for _, album := range page.Inner.Items {
+			ids = append(ids, album.ID)
+		}
+	}
+	full, err := c.GetSeveralAlbums(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var albums []Album
+	for _, album := range full.Albums {
+		if album != nil {
+			albums = append(albums, *album)
+		}
+	}
+	return albums, nil
+}
@@ -0,0 +1,87 @@
+package spotify
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// FromToken is the market that stands for the country of the logged in user's account
+const FromToken = "from_token"
+
+var marketPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// marketPaths are the endpoints that relink or leave out items that aren't
+// available in the market they're given
+var marketPaths = []*regexp.Regexp{
+	regexp.MustCompile(`^/v1/search$`),
+	regexp.MustCompile(`^/v1/(tracks|albums|shows|episodes|audiobooks|chapters)(/[^/]+(/(tracks|episodes|chapters))?)?$`),
+	regexp.MustCompile(`^/v1/playlists/[^/]+(/tracks)?$`),
+	regexp.MustCompile(`^/v1/artists/[^/]+/(top-tracks|albums)$`),
+	regexp.MustCompile(`^/v1/recommendations$`),
+	regexp.MustCompile(`^/v1/me/(tracks|albums|episodes)$`),
+	regexp.MustCompile(`^/v1/me/player/currently-playing$`),
+}
+
+// NormalizeMarket checks that market is an ISO 3166-1 alpha-2 country code or
+// from_token, and uppercases country codes
+func NormalizeMarket(market string) (string, error) {
+	if strings.ToLower(market) == FromToken {
+		return FromToken, nil
+	}
+	upper := strings.ToUpper(market)
+	if !marketPattern.MatchString(upper) {
+		return "", errors.Errorf("Market must be a two letter country code such as SE, or %s", FromToken)
+	}
+	return upper, nil
+}
+
+// SetMarket makes requests to endpoints that take a market use this one
+// unless they're given their own. from_token only works with a user token, so
+// clients without one leave it out.
+func (c *DefaultClient) SetMarket(market string) {
+	c.market = market
+}
+
+// Market is the market set with SetMarket
+func (c *DefaultClient) Market() string {
+	return c.market
+}
+
+// applyMarket adds the client's market to a request that takes one and doesn't
+// have one yet, and drops from_token from requests without a user token
+func (c *DefaultClient) applyMarket(input *req.GetInput) {
+	queryParams := map[string]string{}
+	if input.QueryParams != nil {
+		for k, v := range *input.QueryParams {
+			queryParams[k] = v
+		}
+	}
+	market := queryParams["market"]
+	if market == "" {
+		u, err := url.Parse(input.URL)
+		if err != nil || u.Query().Get("market") != "" || !acceptsMarket(u.Path) {
+			return
+		}
+		market = c.market
+	}
+	if market == "" || (market == FromToken && c.tokenPath == "") {
+		delete(queryParams, "market")
+	} else {
+		queryParams["market"] = market
+	}
+	input.QueryParams = &queryParams
+}
+
+func acceptsMarket(path string) bool {
+	for _, pattern := range marketPaths {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package spotify
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // ClientSecrets -
 type ClientSecrets struct {
 	ClientID     string `json:"clientId"`
@@ -8,27 +13,36 @@ type ClientSecrets struct {
 
 // GetTokenOutput -
 type GetTokenOutput struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
-	Scope       string `json:"scope"`
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	Scope        string    `json:"scope"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
 }
 
 // Category -
 type Category struct {
-	Href string `json:"href"`
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	Href  string  `json:"href"`
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Icons []Image `json:"icons"`
+}
+
+// BrowseInput picks the country and language of browse results and which page of them to get
+type BrowseInput struct {
+	// Country is an ISO 3166-1 alpha-2 code, such as SE
+	Country string
+	// Locale is a language and country code, such as sv_SE
+	Locale string
+	Limit  int
+	Offset int
 }
 
 // GetCategoriesInner -
 type GetCategoriesInner struct {
-	Items    []Category `json:"items"`
-	Limit    int        `json:"limit"`
-	Next     string     `json:"next"`
-	Offset   int        `json:"offset"`
-	Previous *string    `json:"previous"`
-	Total    int        `json:"total"`
+	Paging
+	Items []Category `json:"items"`
 }
 
 // GetCategoriesOutput -
@@ -36,16 +50,32 @@ type GetCategoriesOutput struct {
 	Inner GetCategoriesInner `json:"categories"`
 }
 
+func (o *GetCategoriesOutput) nextPage() string {
+	return o.Inner.Next
+}
+
 // Playlist -
 type Playlist struct {
-	Name          string `json:"name"`
-	URI           string `json:"uri"`
-	Collaborative bool   `json:"collaborative"`
-	Description   string `json:"description"`
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	URI           string        `json:"uri"`
+	Collaborative bool          `json:"collaborative"`
+	Description   string        `json:"description"`
+	Public        *bool         `json:"public"`
+	SnapshotID    string        `json:"snapshot_id"`
+	Owner         User          `json:"owner"`
+	Tracks        PlaylistTotal `json:"tracks"`
+	Images        []Image       `json:"images"`
+}
+
+// PlaylistTotal -
+type PlaylistTotal struct {
+	Total int `json:"total"`
 }
 
 // GetCategoryPlaylistsInner -
 type GetCategoryPlaylistsInner struct {
+	Paging
 	Items []Playlist `json:"items"`
 }
 
@@ -54,28 +84,114 @@ type GetCategoryPlaylistsOutput struct {
 	Inner GetCategoryPlaylistsInner `json:"playlists"`
 }
 
+func (o *GetCategoryPlaylistsOutput) nextPage() string {
+	return o.Inner.Next
+}
+
+// GetFeaturedPlaylistsOutput -
+type GetFeaturedPlaylistsOutput struct {
+	// Message is the headline Spotify shows above the playlists, like "Monday morning music"
+	Message string                    `json:"message"`
+	Inner   GetCategoryPlaylistsInner `json:"playlists"`
+}
+
+func (o *GetFeaturedPlaylistsOutput) nextPage() string {
+	return o.Inner.Next
+}
+
 // Album -
 type Album struct {
-	Name string `json:"name"`
+	ID                   string        `json:"id"`
+	Name                 string        `json:"name"`
+	URI                  string        `json:"uri"`
+	AlbumType            string        `json:"album_type"`
+	AlbumGroup           string        `json:"album_group"`
+	ReleaseDate          string        `json:"release_date"`
+	ReleaseDatePrecision string        `json:"release_date_precision"`
+	TotalTracks          int           `json:"total_tracks"`
+	Artists              []Artist      `json:"artists"`
+	AvailableMarkets     []string      `json:"available_markets,omitempty"`
+	IsPlayable           *bool         `json:"is_playable,omitempty"`
+	Restrictions         *Restrictions `json:"restrictions,omitempty"`
+	ExternalIDs          *ExternalIDs  `json:"external_ids,omitempty"`
+	Label                string        `json:"label,omitempty"`
+	Images               []Image       `json:"images"`
+}
+
+// ExternalIDs are the industry codes of a track or album. Only full track and
+// album objects have them.
+type ExternalIDs struct {
+	ISRC string `json:"isrc,omitempty"`
+	EAN  string `json:"ean,omitempty"`
+	UPC  string `json:"upc,omitempty"`
 }
 
 // Track -
 type Track struct {
-	Album Album `json:"album"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	URI              string        `json:"uri"`
+	Album            Album         `json:"album"`
+	Artists          []Artist      `json:"artists"`
+	DurationMS       int           `json:"duration_ms"`
+	Explicit         bool          `json:"explicit"`
+	Popularity       int           `json:"popularity"`
+	IsLocal          bool          `json:"is_local"`
+	AvailableMarkets []string      `json:"available_markets,omitempty"`
+	IsPlayable       *bool         `json:"is_playable,omitempty"`
+	Restrictions     *Restrictions `json:"restrictions,omitempty"`
+	LinkedFrom       *LinkedTrack  `json:"linked_from,omitempty"`
+	ExternalIDs      *ExternalIDs  `json:"external_ids,omitempty"`
 }
 
-// GetRecommendationsByArtistInner -
-type GetRecommendationsByArtistInner struct {
-	Tracks []Track `json:"tracks"`
+// Restrictions explains why an item can't be played, with a reason of
+// market, product or explicit
+type Restrictions struct {
+	Reason string `json:"reason"`
 }
 
-// GetRecommendationsByArtistOutput -
-type GetRecommendationsByArtistOutput struct {
-	Tracks []Track `json:"tracks"`
+// LinkedTrack is the track that was asked for when Spotify relinked it to a
+// version that's playable in the market
+type LinkedTrack struct {
+	ID   string `json:"id"`
+	URI  string `json:"uri"`
+	Type string `json:"type"`
+	Href string `json:"href"`
+}
+
+// SearchTracksInner -
+type SearchTracksInner struct {
+	Paging
+	Items []Track `json:"items"`
+}
+
+// SearchTracksOutput -
+type SearchTracksOutput struct {
+	Inner SearchTracksInner `json:"tracks"`
+}
+
+func (o *SearchTracksOutput) nextPage() string {
+	return o.Inner.Next
+}
+
+// SearchAlbumsInner -
+type SearchAlbumsInner struct {
+	Paging
+	Items []Album `json:"items"`
+}
+
+// SearchAlbumsOutput -
+type SearchAlbumsOutput struct {
+	Inner SearchAlbumsInner `json:"albums"`
+}
+
+func (o *SearchAlbumsOutput) nextPage() string {
+	return o.Inner.Next
 }
 
 // GetNewReleasesInner -
 type GetNewReleasesInner struct {
+	Paging
 	Items []Album `json:"items"`
 }
 
@@ -84,6 +200,10 @@ type GetNewReleasesOutput struct {
 	Inner GetNewReleasesInner `json:"albums"`
 }
 
+func (o *GetNewReleasesOutput) nextPage() string {
+	return o.Inner.Next
+}
+
 // Followers -
 type Followers struct {
 	Total int `json:"total"`
@@ -91,9 +211,13 @@ type Followers struct {
 
 // Artist -
 type Artist struct {
+	ID         string    `json:"id"`
 	Name       string    `json:"name"`
+	URI        string    `json:"uri"`
+	Genres     []string  `json:"genres"`
 	Popularity int       `json:"popularity"`
 	Followers  Followers `json:"followers"`
+	Images     []Image   `json:"images"`
 }
 
 // GetArtistInner -
@@ -103,6 +227,7 @@ type GetArtistInner struct {
 
 // GetArtistAlbumOutput -
 type GetArtistAlbumOutput struct {
+	Paging
 	Albums []Album `json:"items"`
 }
 
@@ -113,24 +238,30 @@ type GetArtistOutput struct {
 
 // GetAlbumTrack -
 type GetAlbumTrack struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	TrackNumber int    `json:"track_number"`
-	DurationMS  int    `json:"duration_ms"`
-	PreviewURL  string `json:"preview_url"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	URI              string        `json:"uri"`
+	Artists          []Artist      `json:"artists"`
+	TrackNumber      int           `json:"track_number"`
+	DurationMS       int           `json:"duration_ms"`
+	Explicit         bool          `json:"explicit"`
+	PreviewURL       string        `json:"preview_url"`
+	AvailableMarkets []string      `json:"available_markets,omitempty"`
+	IsPlayable       *bool         `json:"is_playable,omitempty"`
+	Restrictions     *Restrictions `json:"restrictions,omitempty"`
+	LinkedFrom       *LinkedTrack  `json:"linked_from,omitempty"`
 }
 
 // GetAlbumTracksOutput -
 type GetAlbumTracksOutput struct {
+	Paging
 	Tracks []GetAlbumTrack `json:"items"`
-	Total  int             `json:"total"`
 }
 
 // GetAlbumOutput -
 type GetAlbumOutput struct {
-	Name       string   `json:"name"`
-	Popularity int      `json:"popularity"`
-	Artists    []Artist `json:"artists"`
+	Album
+	Popularity int `json:"popularity"`
 }
 
 // GetAlbumInner -
@@ -156,3 +287,437 @@ type GetSearchInner struct {
 type SearchItem struct {
 	ID string `json:"id"`
 }
+
+// User -
+type User struct {
+	ID          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	URI         string    `json:"uri"`
+	Email       string    `json:"email"`
+	Country     string    `json:"country"`
+	Product     string    `json:"product"`
+	Followers   Followers `json:"followers"`
+}
+
+// SavedTrack -
+type SavedTrack struct {
+	AddedAt string `json:"added_at"`
+	Track   Track  `json:"track"`
+}
+
+// GetSavedTracksOutput -
+type GetSavedTracksOutput struct {
+	Paging
+	Items []SavedTrack `json:"items"`
+}
+
+// PlaylistTrack -
+type PlaylistTrack struct {
+	AddedAt string `json:"added_at"`
+	Track   *Track `json:"track"`
+}
+
+// GetPlaylistTracksOutput -
+type GetPlaylistTracksOutput struct {
+	Paging
+	Items []PlaylistTrack `json:"items"`
+}
+
+// GetCurrentUserPlaylistsOutput -
+type GetCurrentUserPlaylistsOutput struct {
+	Paging
+	Items []Playlist `json:"items"`
+}
+
+// CreatePlaylistInput -
+type CreatePlaylistInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Public      bool   `json:"public"`
+}
+
+// GetArtistTopTracksOutput -
+type GetArtistTopTracksOutput struct {
+	Tracks []Track `json:"tracks"`
+}
+
+// GetRelatedArtistsOutput -
+type GetRelatedArtistsOutput struct {
+	Artists []Artist `json:"artists"`
+}
+
+// GetArtistDiscographyOutput -
+type GetArtistDiscographyOutput struct {
+	Albums []Album `json:"albums"`
+}
+
+// GetArtistTracksOutput -
+type GetArtistTracksOutput struct {
+	Tracks []Track `json:"tracks"`
+}
+
+// GetRecommendationsInput takes up to five seeds across artists, tracks and
+// genres, and optionally tunables keyed like min_tempo or target_energy
+type GetRecommendationsInput struct {
+	SeedArtists []string
+	SeedTracks  []string
+	SeedGenres  []string
+	Limit       int
+	Market      string
+	Tunables    map[string]float64
+}
+
+// GetRecommendationsOutput -
+type GetRecommendationsOutput struct {
+	Seeds  []RecommendationSeed `json:"seeds"`
+	Tracks []Track              `json:"tracks"`
+}
+
+// RecommendationSeed tells how many tracks were found for a seed
+type RecommendationSeed struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	InitialPoolSize    int    `json:"initialPoolSize"`
+	AfterFilteringSize int    `json:"afterFilteringSize"`
+	AfterRelinkingSize int    `json:"afterRelinkingSize"`
+}
+
+// GetGenreSeedsOutput -
+type GetGenreSeedsOutput struct {
+	Genres []string `json:"genres"`
+}
+
+// GetSeveralTracksOutput -
+type GetSeveralTracksOutput struct {
+	Tracks []*Track `json:"tracks"`
+	// NotFound are the IDs whose tracks are nil
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// GetSeveralArtistsOutput -
+type GetSeveralArtistsOutput struct {
+	Artists  []*Artist `json:"artists"`
+	NotFound []string  `json:"not_found,omitempty"`
+}
+
+// GetSeveralAlbumsOutput -
+type GetSeveralAlbumsOutput struct {
+	Albums   []*Album `json:"albums"`
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// GetSeveralEpisodesOutput -
+type GetSeveralEpisodesOutput struct {
+	Episodes []*Episode `json:"episodes"`
+	NotFound []string   `json:"not_found,omitempty"`
+}
+
+// GetSeveralShowsOutput -
+type GetSeveralShowsOutput struct {
+	Shows    []*Show  `json:"shows"`
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// AudioFeatures -
+type AudioFeatures struct {
+	ID               string  `json:"id"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Key              int     `json:"key"`
+	Loudness         float64 `json:"loudness"`
+	Mode             int     `json:"mode"`
+	Speechiness      float64 `json:"speechiness"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Liveness         float64 `json:"liveness"`
+	Valence          float64 `json:"valence"`
+	Tempo            float64 `json:"tempo"`
+	DurationMS       int     `json:"duration_ms"`
+	TimeSignature    int     `json:"time_signature"`
+	URI              string  `json:"uri"`
+	AnalysisURL      string  `json:"analysis_url"`
+}
+
+// GetAudioFeaturesOutput -
+type GetAudioFeaturesOutput struct {
+	AudioFeatures []*AudioFeatures `json:"audio_features"`
+}
+
+// TimeInterval is a bar, beat or tatum in an audio analysis
+type TimeInterval struct {
+	Start      float64 `json:"start"`
+	Duration   float64 `json:"duration"`
+	Confidence float64 `json:"confidence"`
+}
+
+// AnalysisSection is a stretch of a track with a roughly steady rhythm and timbre
+type AnalysisSection struct {
+	Start                   float64 `json:"start"`
+	Duration                float64 `json:"duration"`
+	Confidence              float64 `json:"confidence"`
+	Loudness                float64 `json:"loudness"`
+	Tempo                   float64 `json:"tempo"`
+	TempoConfidence         float64 `json:"tempo_confidence"`
+	Key                     int     `json:"key"`
+	KeyConfidence           float64 `json:"key_confidence"`
+	Mode                    int     `json:"mode"`
+	ModeConfidence          float64 `json:"mode_confidence"`
+	TimeSignature           int     `json:"time_signature"`
+	TimeSignatureConfidence float64 `json:"time_signature_confidence"`
+}
+
+// AnalysisSegment is a short sound with a steady timbre and pitch
+type AnalysisSegment struct {
+	Start           float64   `json:"start"`
+	Duration        float64   `json:"duration"`
+	Confidence      float64   `json:"confidence"`
+	LoudnessStart   float64   `json:"loudness_start"`
+	LoudnessMax     float64   `json:"loudness_max"`
+	LoudnessMaxTime float64   `json:"loudness_max_time"`
+	LoudnessEnd     float64   `json:"loudness_end"`
+	Pitches         []float64 `json:"pitches"`
+	Timbre          []float64 `json:"timbre"`
+}
+
+// AnalysisTrack is the analysis of the track as a whole
+type AnalysisTrack struct {
+	Duration                float64 `json:"duration"`
+	Loudness                float64 `json:"loudness"`
+	Tempo                   float64 `json:"tempo"`
+	TempoConfidence         float64 `json:"tempo_confidence"`
+	TimeSignature           int     `json:"time_signature"`
+	TimeSignatureConfidence float64 `json:"time_signature_confidence"`
+	Key                     int     `json:"key"`
+	KeyConfidence           float64 `json:"key_confidence"`
+	Mode                    int     `json:"mode"`
+	ModeConfidence          float64 `json:"mode_confidence"`
+	EndOfFadeIn             float64 `json:"end_of_fade_in"`
+	StartOfFadeOut          float64 `json:"start_of_fade_out"`
+}
+
+// AudioAnalysis -
+type AudioAnalysis struct {
+	Track    AnalysisTrack     `json:"track"`
+	Bars     []TimeInterval    `json:"bars"`
+	Beats    []TimeInterval    `json:"beats"`
+	Tatums   []TimeInterval    `json:"tatums"`
+	Sections []AnalysisSection `json:"sections"`
+	Segments []AnalysisSegment `json:"segments"`
+	// Raw is the analysis exactly as returned, including the fields not modelled above
+	Raw json.RawMessage `json:"-"`
+}
+
+// Name is the name object used for audiobook authors and narrators
+type Name struct {
+	Name string `json:"name"`
+}
+
+// ResumePoint -
+type ResumePoint struct {
+	FullyPlayed      bool `json:"fully_played"`
+	ResumePositionMS int  `json:"resume_position_ms"`
+}
+
+// Image is artwork in one size, largest first when there are several
+type Image struct {
+	URL    string `json:"url"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+}
+
+// Show -
+type Show struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	URI              string   `json:"uri"`
+	Publisher        string   `json:"publisher"`
+	Description      string   `json:"description"`
+	MediaType        string   `json:"media_type"`
+	Explicit         bool     `json:"explicit"`
+	Languages        []string `json:"languages"`
+	TotalEpisodes    int      `json:"total_episodes"`
+	Images           []Image  `json:"images"`
+	AvailableMarkets []string `json:"available_markets,omitempty"`
+}
+
+// Episode -
+type Episode struct {
+	ID                   string        `json:"id"`
+	Name                 string        `json:"name"`
+	URI                  string        `json:"uri"`
+	Description          string        `json:"description"`
+	DurationMS           int           `json:"duration_ms"`
+	Explicit             bool          `json:"explicit"`
+	ReleaseDate          string        `json:"release_date"`
+	ReleaseDatePrecision string        `json:"release_date_precision"`
+	ResumePoint          *ResumePoint  `json:"resume_point"`
+	Images               []Image       `json:"images"`
+	Show                 *Show         `json:"show"`
+	IsPlayable           *bool         `json:"is_playable,omitempty"`
+	Restrictions         *Restrictions `json:"restrictions,omitempty"`
+}
+
+// GetShowEpisodesOutput -
+type GetShowEpisodesOutput struct {
+	Paging
+	Items []Episode `json:"items"`
+}
+
+// Audiobook -
+type Audiobook struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	URI              string   `json:"uri"`
+	Authors          []Name   `json:"authors"`
+	Narrators        []Name   `json:"narrators"`
+	Publisher        string   `json:"publisher"`
+	Description      string   `json:"description"`
+	Edition          string   `json:"edition"`
+	Explicit         bool     `json:"explicit"`
+	Languages        []string `json:"languages"`
+	TotalChapters    int      `json:"total_chapters"`
+	Images           []Image  `json:"images"`
+	AvailableMarkets []string `json:"available_markets,omitempty"`
+}
+
+// Chapter -
+type Chapter struct {
+	ID                   string        `json:"id"`
+	Name                 string        `json:"name"`
+	URI                  string        `json:"uri"`
+	ChapterNumber        int           `json:"chapter_number"`
+	Description          string        `json:"description"`
+	DurationMS           int           `json:"duration_ms"`
+	Explicit             bool          `json:"explicit"`
+	Languages            []string      `json:"languages"`
+	ReleaseDate          string        `json:"release_date"`
+	ReleaseDatePrecision string        `json:"release_date_precision"`
+	ResumePoint          *ResumePoint  `json:"resume_point"`
+	Images               []Image       `json:"images"`
+	Audiobook            *Audiobook    `json:"audiobook"`
+	AvailableMarkets     []string      `json:"available_markets,omitempty"`
+	IsPlayable           *bool         `json:"is_playable,omitempty"`
+	Restrictions         *Restrictions `json:"restrictions,omitempty"`
+}
+
+// GetAudiobookChaptersOutput -
+type GetAudiobookChaptersOutput struct {
+	Paging
+	Items []Chapter `json:"items"`
+}
+
+// SavedAlbum -
+type SavedAlbum struct {
+	AddedAt string `json:"added_at"`
+	Album   Album  `json:"album"`
+}
+
+// GetSavedAlbumsOutput -
+type GetSavedAlbumsOutput struct {
+	Paging
+	Items []SavedAlbum `json:"items"`
+}
+
+// SavedShow -
+type SavedShow struct {
+	AddedAt string `json:"added_at"`
+	Show    Show   `json:"show"`
+}
+
+// GetSavedShowsOutput -
+type GetSavedShowsOutput struct {
+	Paging
+	Items []SavedShow `json:"items"`
+}
+
+// SavedEpisode -
+type SavedEpisode struct {
+	AddedAt string  `json:"added_at"`
+	Episode Episode `json:"episode"`
+}
+
+// GetSavedEpisodesOutput -
+type GetSavedEpisodesOutput struct {
+	Paging
+	Items []SavedEpisode `json:"items"`
+}
+
+// GetSavedAudiobooksOutput -
+type GetSavedAudiobooksOutput struct {
+	Paging
+	Items []Audiobook `json:"items"`
+}
+
+// Cursors -
+type Cursors struct {
+	After  string `json:"after"`
+	Before string `json:"before"`
+}
+
+// FollowedArtistsPage is a cursor based page of artists
+type FollowedArtistsPage struct {
+	Items   []Artist `json:"items"`
+	Next    string   `json:"next"`
+	Cursors Cursors  `json:"cursors"`
+	Limit   int      `json:"limit"`
+	Total   int      `json:"total"`
+}
+
+// GetFollowedArtistsOutput -
+type GetFollowedArtistsOutput struct {
+	Inner FollowedArtistsPage `json:"artists"`
+}
+
+func (o *GetFollowedArtistsOutput) nextPage() string {
+	return o.Inner.Next
+}
+
+// Time ranges accepted by the top items endpoints
+const (
+	TimeRangeShort  = "short_term"
+	TimeRangeMedium = "medium_term"
+	TimeRangeLong   = "long_term"
+)
+
+// GetTopArtistsOutput -
+type GetTopArtistsOutput struct {
+	Paging
+	Items []Artist `json:"items"`
+}
+
+// GetTopTracksOutput -
+type GetTopTracksOutput struct {
+	Paging
+	Items []Track `json:"items"`
+}
+
+// PlayContext is what a track was played from, such as an album or playlist
+type PlayContext struct {
+	Type string `json:"type"`
+	URI  string `json:"uri"`
+}
+
+// CurrentlyPlaying -
+type CurrentlyPlaying struct {
+	IsPlaying  bool         `json:"is_playing"`
+	ProgressMS int          `json:"progress_ms"`
+	Item       *Track       `json:"item"`
+	Context    *PlayContext `json:"context"`
+	// CurrentlyPlayingType is track, episode, ad or unknown
+	CurrentlyPlayingType string `json:"currently_playing_type"`
+}
+
+// PlayHistory -
+type PlayHistory struct {
+	Track    Track        `json:"track"`
+	PlayedAt time.Time    `json:"played_at"`
+	Context  *PlayContext `json:"context"`
+}
+
+// GetRecentlyPlayedOutput -
+type GetRecentlyPlayedOutput struct {
+	Items   []PlayHistory `json:"items"`
+	Next    string        `json:"next"`
+	Cursors Cursors       `json:"cursors"`
+	Limit   int           `json:"limit"`
+}
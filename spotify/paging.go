@@ -0,0 +1,49 @@
+package spotify
+
+import (
+	"context"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// Paging holds the fields the Web API wraps every list response in
+type Paging struct {
+	Href     string  `json:"href"`
+	Limit    int     `json:"limit"`
+	Next     string  `json:"next"`
+	Offset   int     `json:"offset"`
+	Previous *string `json:"previous"`
+	Total    int     `json:"total"`
+}
+
+func (p *Paging) nextPage() string {
+	return p.Next
+}
+
+// pager is implemented by any response that embeds Paging
+type pager interface {
+	nextPage() string
+}
+
+// getAllPages requests input and then follows each page's next link until the
+// last page. newPage is called once per request and must return a fresh
+// destination, which lets the caller collect the pages as they come in.
+func (c *DefaultClient) getAllPages(ctx context.Context, input *req.GetInput, newPage func() pager) error {
+	for {
+		page := newPage()
+		input.Destination = page
+		if err := c.get(ctx, input); err != nil {
+			return errors.WithMessage(err, "Failed to get page")
+		}
+
+		next := page.nextPage()
+		if next == "" {
+			return nil
+		}
+		input = &req.GetInput{
+			URL: next,
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package spotify
+
+import (
+	"context"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetCurrentUserPlaylists returns every playlist the user owns or follows
+func (c *DefaultClient) GetCurrentUserPlaylists(ctx context.Context) (*GetCurrentUserPlaylistsOutput, error) {
+	var pages []*GetCurrentUserPlaylistsOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/me/playlists",
+		QueryParams: &map[string]string{
+			"limit": "50",
+		},
+	}, func() pager {
+		page := &GetCurrentUserPlaylistsOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get current user's playlists")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// GetPlaylist gets a playlist's details, without its items
+func (c *DefaultClient) GetPlaylist(ctx context.Context, playlist string) (*Playlist, error) {
+	playlistID, err := c.ResolveID(ctx, playlist, "playlist")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for playlist")
+	}
+	var output Playlist
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/playlists/{playlistID}",
+		Slugs: &map[string]string{
+			"{playlistID}": playlistID,
+		},
+		QueryParams: &map[string]string{
+			"fields": "id,name,uri,collaborative,description,public,snapshot_id,owner,tracks(total),images",
+		},
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get playlist")
+	}
+	return &output, nil
+}
+
+// GetPlaylistTracks returns every item of a playlist given by ID, URI or link.
+// Episodes are returned as tracks carrying just their ID, name and URI.
+func (c *DefaultClient) GetPlaylistTracks(ctx context.Context, playlist string) (*GetPlaylistTracksOutput, error) {
+	playlistID, ok := ParseID(playlist, "playlist")
+	if !ok {
+		playlistID = playlist
+	}
+
+	var pages []*GetPlaylistTracksOutput
+	if err := c.getAllPages(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/playlists/{playlistID}/tracks",
+		Slugs: &map[string]string{
+			"{playlistID}": playlistID,
+		},
+		QueryParams: &map[string]string{
+			"limit":            "100",
+			"additional_types": "track,episode",
+		},
+	}, func() pager {
+		page := &GetPlaylistTracksOutput{}
+		pages = append(pages, page)
+		return page
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get playlist tracks")
+	}
+
+	output := pages[0]
+	for _, page := range pages[1:] {
+		output.Items = append(output.Items, page.Items...)
+	}
+	return output, nil
+}
+
+// CreatePlaylist -
+func (c *DefaultClient) CreatePlaylist(ctx context.Context, userID string, input *CreatePlaylistInput) (*Playlist, error) {
+	var output Playlist
+	if err := c.post(ctx, &req.PostInput{
+		URL: "https://api.spotify.com/v1/users/{userID}/playlists",
+		Slugs: &map[string]string{
+			"{userID}": userID,
+		},
+		JSONBody:    input,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to create playlist")
+	}
+	return &output, nil
+}
+
+// ReplacePlaylistTracks sets the playlist's items to uris, in order. The API
+// only takes 100 items per request so anything past the first 100 is appended
+// afterwards.
+func (c *DefaultClient) ReplacePlaylistTracks(ctx context.Context, playlistID string, uris []string) error {
+	chunks := Chunk(uris, 100)
+	first := []string{}
+	if len(chunks) > 0 {
+		first = chunks[0]
+	}
+	slugs := &map[string]string{
+		"{playlistID}": playlistID,
+	}
+
+	if err := c.put(ctx, &req.PutInput{
+		URL:   "https://api.spotify.com/v1/playlists/{playlistID}/tracks",
+		Slugs: slugs,
+		JSONBody: map[string][]string{
+			"uris": first,
+		},
+	}); err != nil {
+		return errors.WithMessage(err, "Failed to replace playlist tracks")
+	}
+
+	return c.AddPlaylistTracks(ctx, playlistID, uris[len(first):])
+}
+
+// AddPlaylistTracks appends uris to the end of the playlist, 100 per request
+func (c *DefaultClient) AddPlaylistTracks(ctx context.Context, playlistID string, uris []string) error {
+	for _, uris := range Chunk(uris, 100) {
+		if err := c.post(ctx, &req.PostInput{
+			URL: "https://api.spotify.com/v1/playlists/{playlistID}/tracks",
+			Slugs: &map[string]string{
+				"{playlistID}": playlistID,
+			},
+			JSONBody: map[string][]string{
+				"uris": uris,
+			},
+		}); err != nil {
+			return errors.WithMessage(err, "Failed to add playlist tracks")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// maxSeeds is the most artists, tracks and genres recommendations can be seeded with, together
+const maxSeeds = 5
+
+// TunableAttributes can be given a min_, max_ or target_ value when getting recommendations
+var TunableAttributes = []string{
+	"acousticness", "danceability", "duration_ms", "energy", "instrumentalness", "key", "liveness",
+	"loudness", "mode", "popularity", "speechiness", "tempo", "time_signature", "valence",
+}
+
+// GetRecommendations gets tracks like the seeds. Seeds must be IDs, or genre
+// names as listed by GetGenreSeeds.
+func (c *DefaultClient) GetRecommendations(ctx context.Context, input *GetRecommendationsInput) (*GetRecommendationsOutput, error) {
+	seeds := len(input.SeedArtists) + len(input.SeedTracks) + len(input.SeedGenres)
+	if seeds == 0 || seeds > maxSeeds {
+		return nil, errors.Errorf("Recommendations need between 1 and %d seeds, got %d", maxSeeds, seeds)
+	}
+
+	queryParams := map[string]string{}
+	if len(input.SeedArtists) > 0 {
+		queryParams["seed_artists"] = strings.Join(input.SeedArtists, ",")
+	}
+	if len(input.SeedTracks) > 0 {
+		queryParams["seed_tracks"] = strings.Join(input.SeedTracks, ",")
+	}
+	if len(input.SeedGenres) > 0 {
+		queryParams["seed_genres"] = strings.Join(input.SeedGenres, ",")
+	}
+	if input.Limit > 0 {
+		queryParams["limit"] = strconv.Itoa(input.Limit)
+	}
+	if input.Market != "" {
+		queryParams["market"] = input.Market
+	}
+	for name, value := range input.Tunables {
+		if !IsTunable(name) {
+			return nil, errors.Errorf("Unknown tunable %q", name)
+		}
+		queryParams[name] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	var output GetRecommendationsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/recommendations",
+		QueryParams: &queryParams,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get recommendations")
+	}
+	return &output, nil
+}
+
+// GetGenreSeeds lists the genres recommendations can be seeded with
+func (c *DefaultClient) GetGenreSeeds(ctx context.Context) (*GetGenreSeedsOutput, error) {
+	var output GetGenreSeedsOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/recommendations/available-genre-seeds",
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get genre seeds")
+	}
+	return &output, nil
+}
+
+// IsTunable reports whether name is a tunable such as min_tempo or target_energy
+func IsTunable(name string) bool {
+	for _, prefix := range []string{"min_", "max_", "target_"} {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		attribute := strings.TrimPrefix(name, prefix)
+		for _, tunable := range TunableAttributes {
+			if attribute == tunable {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package spotify
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cwseger/spotify-cli/parallel"
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// defaultConcurrency is how many chunks the GetSeveral methods request at once
+// unless SetConcurrency says otherwise
+const defaultConcurrency = 4
+
+// SetConcurrency sets how many requests the GetSeveral methods make at once
+func (c *DefaultClient) SetConcurrency(concurrency int) {
+	c.concurrency = concurrency
+}
+
+// GetSeveralTracks looks up any number of tracks, 50 per request. Tracks come
+// back in the order of ids, with the ones that don't exist nil and in NotFound.
+func (c *DefaultClient) GetSeveralTracks(ctx context.Context, ids []string) (*GetSeveralTracksOutput, error) {
+	chunks := Chunk(ids, 50)
+	pages := make([]GetSeveralTracksOutput, len(chunks))
+	if err := c.getSeveral(ctx, "https://api.spotify.com/v1/tracks", chunks, func(i int) interface{} {
+		return &pages[i]
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get several tracks")
+	}
+
+	var output GetSeveralTracksOutput
+	for i, page := range pages {
+		for j, id := range chunks[i] {
+			var track *Track
+			if j < len(page.Tracks) {
+				track = page.Tracks[j]
+			}
+			if track == nil {
+				output.NotFound = append(output.NotFound, id)
+			}
+			output.Tracks = append(output.Tracks, track)
+		}
+	}
+	return &output, nil
+}
+
+// GetSeveralArtists looks up any number of artists, 50 per request. Artists
+// come back in the order of ids, with the ones that don't exist nil and in NotFound.
+func (c *DefaultClient) GetSeveralArtists(ctx context.Context, ids []string) (*GetSeveralArtistsOutput, error) {
+	chunks := Chunk(ids, 50)
+	pages := make([]GetSeveralArtistsOutput, len(chunks))
+	if err := c.getSeveral(ctx, "https://api.spotify.com/v1/artists", chunks, func(i int) interface{} {
+		return &pages[i]
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get several artists")
+	}
+
+	var output GetSeveralArtistsOutput
+	for i, page := range pages {
+		for j, id := range chunks[i] {
+			var artist *Artist
+			if j < len(page.Artists) {
+				artist = page.Artists[j]
+			}
+			if artist == nil {
+				output.NotFound = append(output.NotFound, id)
+			}
+			output.Artists = append(output.Artists, artist)
+		}
+	}
+	return &output, nil
+}
+
+// GetSeveralAlbums looks up any number of albums, 20 per request. Albums come
+// back in the order of ids, with the ones that don't exist nil and in NotFound.
+func (c *DefaultClient) GetSeveralAlbums(ctx context.Context, ids []string) (*GetSeveralAlbumsOutput, error) {
+	chunks := Chunk(ids, 20)
+	pages := make([]GetSeveralAlbumsOutput, len(chunks))
+	if err := c.getSeveral(ctx, "https://api.spotify.com/v1/albums", chunks, func(i int) interface{} {
+		return &pages[i]
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get several albums")
+	}
+
+	var output GetSeveralAlbumsOutput
+	for i, page := range pages {
+		for j, id := range chunks[i] {
+			var album *Album
+			if j < len(page.Albums) {
+				album = page.Albums[j]
+			}
+			if album == nil {
+				output.NotFound = append(output.NotFound, id)
+			}
+			output.Albums = append(output.Albums, album)
+		}
+	}
+	return &output, nil
+}
+
+// GetSeveralEpisodes looks up any number of episodes, 50 per request.
+// Episodes come back in the order of ids, with the ones that don't exist, or
+// aren't available in the market, nil and in NotFound.
+func (c *DefaultClient) GetSeveralEpisodes(ctx context.Context, ids []string) (*GetSeveralEpisodesOutput, error) {
+	chunks := Chunk(ids, 50)
+	pages := make([]GetSeveralEpisodesOutput, len(chunks))
+	if err := c.getSeveral(ctx, "https://api.spotify.com/v1/episodes", chunks, func(i int) interface{} {
+		return &pages[i]
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get several episodes")
+	}
+
+	var output GetSeveralEpisodesOutput
+	for i, page := range pages {
+		for j, id := range chunks[i] {
+			var episode *Episode
+			if j < len(page.Episodes) {
+				episode = page.Episodes[j]
+			}
+			if episode == nil {
+				output.NotFound = append(output.NotFound, id)
+			}
+			output.Episodes = append(output.Episodes, episode)
+		}
+	}
+	return &output, nil
+}
+
+// GetSeveralShows looks up any number of shows, 50 per request. Shows come
+// back in the order of ids, with the ones that don't exist, or aren't
+// available in the market, nil and in NotFound.
+func (c *DefaultClient) GetSeveralShows(ctx context.Context, ids []string) (*GetSeveralShowsOutput, error) {
+	chunks := Chunk(ids, 50)
+	pages := make([]GetSeveralShowsOutput, len(chunks))
+	if err := c.getSeveral(ctx, "https://api.spotify.com/v1/shows", chunks, func(i int) interface{} {
+		return &pages[i]
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get several shows")
+	}
+
+	var output GetSeveralShowsOutput
+	for i, page := range pages {
+		for j, id := range chunks[i] {
+			var show *Show
+			if j < len(page.Shows) {
+				show = page.Shows[j]
+			}
+			if show == nil {
+				output.NotFound = append(output.NotFound, id)
+			}
+			output.Shows = append(output.Shows, show)
+		}
+	}
+	return &output, nil
+}
+
+// getSeveral requests each chunk of IDs from url, a few chunks at a time.
+// destination is called with the index of a chunk and returns what to decode
+// its response into. The first error cancels the chunks that haven't started.
+func (c *DefaultClient) getSeveral(ctx context.Context, url string, chunks [][]string, destination func(i int) interface{}) error {
+	concurrency := c.concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	return parallel.ForEach(ctx, len(chunks), concurrency, func(ctx context.Context, i int) error {
+		return c.get(ctx, &req.GetInput{
+			URL: url,
+			QueryParams: &map[string]string{
+				"ids": strings.Join(chunks[i], ","),
+			},
+			Destination: destination(i),
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetShow gets a show's details. A market is needed for most shows unless the
+// client has a user token, in which case the user's country is used.
+func (c *DefaultClient) GetShow(ctx context.Context, show, market string) (*Show, error) {
+	showID, err := c.ResolveID(ctx, show, "show")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for show")
+	}
+	var output Show
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/shows/{showID}",
+		Slugs: &map[string]string{
+			"{showID}": showID,
+		},
+		QueryParams: marketParams(market),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get show")
+	}
+	return &output, nil
+}
+
+// GetShowEpisodes gets a page of up to 50 of a show's episodes, newest first
+func (c *DefaultClient) GetShowEpisodes(ctx context.Context, showID, market string, limit, offset int) (*GetShowEpisodesOutput, error) {
+	queryParams := marketParams(market)
+	(*queryParams)["limit"] = strconv.Itoa(limit)
+	(*queryParams)["offset"] = strconv.Itoa(offset)
+	var output GetShowEpisodesOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/shows/{showID}/episodes",
+		Slugs: &map[string]string{
+			"{showID}": showID,
+		},
+		QueryParams: queryParams,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get show episodes")
+	}
+	return &output, nil
+}
+
+// GetEpisode gets an episode and the show it belongs to. The resume point is
+// only filled in for clients with a user token.
+func (c *DefaultClient) GetEpisode(ctx context.Context, episode, market string) (*Episode, error) {
+	episodeID, err := c.ResolveID(ctx, episode, "episode")
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to get spotify id for episode")
+	}
+	var output Episode
+	if err := c.get(ctx, &req.GetInput{
+		URL: "https://api.spotify.com/v1/episodes/{episodeID}",
+		Slugs: &map[string]string{
+			"{episodeID}": episodeID,
+		},
+		QueryParams: marketParams(market),
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get episode")
+	}
+	return &output, nil
+}
+
+func marketParams(market string) *map[string]string {
+	queryParams := map[string]string{}
+	if market != "" {
+		queryParams["market"] = market
+	}
+	return &queryParams
+}
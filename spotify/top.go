@@ -0,0 +1,68 @@
+package spotify
+
+import (
+	"context"
+	"strconv"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// topPageSize is the most items the top items endpoints return per page
+const topPageSize = 50
+
+// GetTopArtists returns up to limit of the user's top artists over timeRange,
+// paging through as many requests as that takes.
+func (c *DefaultClient) GetTopArtists(ctx context.Context, timeRange string, limit int) (*GetTopArtistsOutput, error) {
+	var output GetTopArtistsOutput
+	for offset := 0; offset < limit; offset += topPageSize {
+		var page GetTopArtistsOutput
+		if err := c.get(ctx, c.topItemsInput("artists", timeRange, offset, limit, &page)); err != nil {
+			return nil, errors.WithMessage(err, "Failed to get top artists")
+		}
+		output.Paging = page.Paging
+		output.Items = append(output.Items, page.Items...)
+		if page.Next == "" {
+			break
+		}
+	}
+	return &output, nil
+}
+
+// GetTopTracks returns up to limit of the user's top tracks over timeRange,
+// paging through as many requests as that takes.
+func (c *DefaultClient) GetTopTracks(ctx context.Context, timeRange string, limit int) (*GetTopTracksOutput, error) {
+	var output GetTopTracksOutput
+	for offset := 0; offset < limit; offset += topPageSize {
+		var page GetTopTracksOutput
+		if err := c.get(ctx, c.topItemsInput("tracks", timeRange, offset, limit, &page)); err != nil {
+			return nil, errors.WithMessage(err, "Failed to get top tracks")
+		}
+		output.Paging = page.Paging
+		output.Items = append(output.Items, page.Items...)
+		if page.Next == "" {
+			break
+		}
+	}
+	return &output, nil
+}
+
+func (c *DefaultClient) topItemsInput(itemType, timeRange string, offset, limit int, destination interface{}) *req.GetInput {
+	pageSize := limit - offset
+	if pageSize > topPageSize {
+		pageSize = topPageSize
+	}
+	return &req.GetInput{
+		URL: "https://api.spotify.com/v1/me/top/{type}",
+		Slugs: &map[string]string{
+			"{type}": itemType,
+		},
+		QueryParams: &map[string]string{
+			"time_range": timeRange,
+			"limit":      strconv.Itoa(pageSize),
+			"offset":     strconv.Itoa(offset),
+		},
+		Destination: destination,
+	}
+}
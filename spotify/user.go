@@ -0,0 +1,62 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	req "github.com/cwseger/spotify-cli/req"
+
+	"github.com/pkg/errors"
+)
+
+// GetCurrentUser -
+func (c *DefaultClient) GetCurrentUser(ctx context.Context) (*User, error) {
+	var output User
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/me",
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get current user")
+	}
+	return &output, nil
+}
+
+// GetCurrentlyPlaying returns the track the user is listening to. Item is nil
+// when nothing is playing, or when it's an episode or an ad.
+func (c *DefaultClient) GetCurrentlyPlaying(ctx context.Context) (*CurrentlyPlaying, error) {
+	var output CurrentlyPlaying
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/me/player/currently-playing",
+		Destination: &output,
+	}); err != nil {
+		// Logins from before the user-read-currently-playing scope was
+		// added are turned away
+		if respErr, ok := err.(*req.ResponseError); ok && (respErr.StatusCode == http.StatusUnauthorized || respErr.StatusCode == http.StatusForbidden) {
+			return nil, ErrMissingPermission
+		}
+		return nil, errors.WithMessage(err, "Failed to get currently playing track")
+	}
+	return &output, nil
+}
+
+// GetRecentlyPlayed returns up to 50 of the user's most recent plays that
+// started after the given time. A zero time returns the latest 50.
+func (c *DefaultClient) GetRecentlyPlayed(ctx context.Context, after time.Time) (*GetRecentlyPlayedOutput, error) {
+	queryParams := map[string]string{
+		"limit": "50",
+	}
+	if !after.IsZero() {
+		queryParams["after"] = strconv.FormatInt(after.UnixNano()/int64(time.Millisecond), 10)
+	}
+	var output GetRecentlyPlayedOutput
+	if err := c.get(ctx, &req.GetInput{
+		URL:         "https://api.spotify.com/v1/me/player/recently-played",
+		QueryParams: &queryParams,
+		Destination: &output,
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to get recently played tracks")
+	}
+	return &output, nil
+}
@@ -0,0 +1,62 @@
+package stats
+
+import "time"
+
+// Count is how much something was listened to
+type Count struct {
+	ID      string  `json:"id,omitempty"`
+	Name    string  `json:"name"`
+	Detail  string  `json:"detail,omitempty"`
+	Plays   int     `json:"plays"`
+	Minutes float64 `json:"minutes"`
+}
+
+// Streak is a run of consecutive days with at least one play
+type Streak struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Days  int       `json:"days"`
+}
+
+// Discovery counts what was heard for the first time in the report's range
+type Discovery struct {
+	NewTracks  int `json:"new_tracks"`
+	NewArtists int `json:"new_artists"`
+	// TrackRate and ArtistRate are the share of unique tracks and artists that were new
+	TrackRate  float64 `json:"track_rate"`
+	ArtistRate float64 `json:"artist_rate"`
+}
+
+// Report is everything worked out from the plays in a date range
+type Report struct {
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Plays         int       `json:"plays"`
+	Minutes       float64   `json:"minutes"`
+	ActiveDays    int       `json:"active_days"`
+	UniqueTracks  int       `json:"unique_tracks"`
+	UniqueArtists int       `json:"unique_artists"`
+	UniqueAlbums  int       `json:"unique_albums"`
+
+	Artists []Count `json:"artists"`
+	Albums  []Count `json:"albums"`
+	Tracks  []Count `json:"tracks"`
+	// Genres is empty when genres weren't looked up
+	Genres []Count `json:"genres"`
+
+	// Hours and Weekdays count plays by local hour of day and by weekday, Sunday first
+	Hours    [24]Count `json:"hours"`
+	Weekdays [7]Count  `json:"weekdays"`
+	// Heatmap counts plays by weekday, Sunday first, and hour
+	Heatmap [7][24]int `json:"heatmap"`
+	// Months counts plays by calendar month, in order
+	Months []Count `json:"months"`
+
+	BusiestDay    Count     `json:"busiest_day"`
+	LongestStreak Streak    `json:"longest_streak"`
+	CurrentStreak Streak    `json:"current_streak"`
+	Discovery     Discovery `json:"discovery"`
+}
+
+// GenreLookup returns the genres of each of the given artists
+type GenreLookup func(artistIDs []string) (map[string][]string, error)
@@ -0,0 +1,346 @@
+package stats
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	barWidth = 40
+	// shades go from no plays to the busiest hour of the week
+	shades = " .:-=+*#%@"
+)
+
+var weekdayOrder = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// RenderText writes the report as tables and bar charts for the terminal
+func RenderText(w io.Writer, r *Report) error {
+	p := &printer{w: w}
+	p.line("Listening from %s to %s", formatDate(r.From), formatDate(r.To))
+	p.line("")
+	p.line("Plays:          %d", r.Plays)
+	p.line("Minutes:        %s (%s)", formatMinutes(r.Minutes), formatHours(r.Minutes))
+	p.line("Active days:    %d", r.ActiveDays)
+	p.line("Unique tracks:  %d", r.UniqueTracks)
+	p.line("Unique artists: %d", r.UniqueArtists)
+	p.line("Unique albums:  %d", r.UniqueAlbums)
+	if r.BusiestDay.Plays > 0 {
+		p.line("Busiest day:    %s, %d plays", r.BusiestDay.Name, r.BusiestDay.Plays)
+	}
+	p.line("Longest streak: %s", formatStreak(r.LongestStreak))
+	p.line("Current streak: %s", formatStreak(r.CurrentStreak))
+	p.line("New tracks:     %d (%.0f%% of unique tracks)", r.Discovery.NewTracks, r.Discovery.TrackRate*100)
+	p.line("New artists:    %d (%.0f%% of unique artists)", r.Discovery.NewArtists, r.Discovery.ArtistRate*100)
+
+	p.table("Top artists", r.Artists, false)
+	p.table("Top albums", r.Albums, true)
+	p.table("Top tracks", r.Tracks, true)
+	if len(r.Genres) > 0 {
+		p.table("Top genres", r.Genres, false)
+	}
+
+	p.chart("Plays by hour", r.Hours[:])
+	weekdays := make([]Count, 0, len(weekdayOrder))
+	for _, day := range weekdayOrder {
+		weekdays = append(weekdays, r.Weekdays[day])
+	}
+	p.chart("Plays by weekday", weekdays)
+	if len(r.Months) > 1 {
+		p.chart("Plays by month", r.Months)
+	}
+
+	p.line("")
+	p.line("Heatmap (hour of day, darker is busier)")
+	p.line("     0         1         2")
+	p.line("     012345678901234567890123")
+	busiest := maxHeat(r.Heatmap)
+	for _, day := range weekdayOrder {
+		var row strings.Builder
+		for _, plays := range r.Heatmap[day] {
+			row.WriteByte(shades[shade(plays, busiest, len(shades))])
+		}
+		p.line("%s  %s", day.String()[:3], row.String())
+	}
+	return p.err
+}
+
+// RenderReview writes a short year in review style summary of the report
+func RenderReview(w io.Writer, r *Report) error {
+	p := &printer{w: w}
+	if r.Plays == 0 {
+		p.line("Nothing was played between %s and %s", formatDate(r.From), formatDate(r.To))
+		return p.err
+	}
+	p.line("Your listening from %s to %s", formatDate(r.From), formatDate(r.To))
+	p.line("")
+	p.line("You listened for %s across %d plays, on %d different days.", formatHours(r.Minutes), r.Plays, r.ActiveDays)
+	p.line("That was %d different tracks by %d artists, %d of them heard for the first time.", r.UniqueTracks, r.UniqueArtists, r.Discovery.NewArtists)
+	if len(r.Artists) > 0 {
+		p.line("Your top artist was %s with %d plays.", r.Artists[0].Name, r.Artists[0].Plays)
+	}
+	if len(r.Tracks) > 0 {
+		p.line("Your top track was %s by %s, played %d times.", r.Tracks[0].Name, r.Tracks[0].Detail, r.Tracks[0].Plays)
+	}
+	if len(r.Albums) > 0 {
+		p.line("Your top album was %s by %s.", r.Albums[0].Name, r.Albums[0].Detail)
+	}
+	if len(r.Genres) > 0 {
+		var genres []string
+		for i := 0; i < len(r.Genres) && i < 3; i++ {
+			genres = append(genres, r.Genres[i].Name)
+		}
+		p.line("You mostly listened to %s.", strings.Join(genres, ", "))
+	}
+	if hour := busiestCount(r.Hours[:]); hour.Plays > 0 {
+		p.line("You listened most at %s, and most on %ss.", hour.Name, busiestCount(r.Weekdays[:]).Name)
+	}
+	if r.BusiestDay.Plays > 0 {
+		p.line("Your busiest day was %s with %d plays.", r.BusiestDay.Name, r.BusiestDay.Plays)
+	}
+	if r.LongestStreak.Days > 1 {
+		p.line("Your longest streak was %s.", formatStreak(r.LongestStreak))
+	}
+	return p.err
+}
+
+// RenderHTML writes the report as a single HTML page with no outside resources
+func RenderHTML(w io.Writer, r *Report) error {
+	weekdays := make([]Count, 0, len(weekdayOrder))
+	heatmap := make([]heatRow, 0, len(weekdayOrder))
+	busiest := maxHeat(r.Heatmap)
+	for _, day := range weekdayOrder {
+		weekdays = append(weekdays, r.Weekdays[day])
+		row := heatRow{Day: day.String()[:3]}
+		for _, plays := range r.Heatmap[day] {
+			row.Cells = append(row.Cells, heatCell{Plays: plays, Level: shade(plays, busiest, 5)})
+		}
+		heatmap = append(heatmap, row)
+	}
+
+	data := struct {
+		*Report
+		HourChart    []Count
+		WeekdayChart []Count
+		Heatmap      []heatRow
+	}{r, r.Hours[:], weekdays, heatmap}
+	if err := htmlReport.Execute(w, data); err != nil {
+		return errors.WithMessage(err, "Failed to render HTML report")
+	}
+	return nil
+}
+
+type heatRow struct {
+	Day   string
+	Cells []heatCell
+}
+
+type heatCell struct {
+	Plays int
+	Level int
+}
+
+// printer writes lines until the first error, which is kept for the end
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) line(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format+"\n", args...)
+}
+
+func (p *printer) table(title string, counts []Count, detail bool) {
+	p.line("")
+	p.line(title)
+	for i, count := range counts {
+		name := count.Name
+		if detail && count.Detail != "" {
+			name += " - " + count.Detail
+		}
+		p.line("%3d. %s | %d plays | %s min", i+1, name, count.Plays, formatMinutes(count.Minutes))
+	}
+}
+
+func (p *printer) chart(title string, counts []Count) {
+	p.line("")
+	p.line(title)
+	busiest := busiestCount(counts).Plays
+	width := 0
+	for _, count := range counts {
+		if len(count.Name) > width {
+			width = len(count.Name)
+		}
+	}
+	for _, count := range counts {
+		bar := 0
+		if busiest > 0 {
+			bar = int(math.Round(float64(count.Plays) / float64(busiest) * barWidth))
+		}
+		p.line("%-*s %s %d", width, count.Name, strings.Repeat("#", bar), count.Plays)
+	}
+}
+
+func busiestCount(counts []Count) Count {
+	var busiest Count
+	for _, count := range counts {
+		if count.Plays > busiest.Plays {
+			busiest = count
+		}
+	}
+	return busiest
+}
+
+func maxHeat(heatmap [7][24]int) int {
+	busiest := 0
+	for _, day := range heatmap {
+		for _, plays := range day {
+			if plays > busiest {
+				busiest = plays
+			}
+		}
+	}
+	return busiest
+}
+
+// shade scales plays to one of levels steps, keeping the first for no plays at all
+func shade(plays, busiest, levels int) int {
+	if plays == 0 || busiest == 0 {
+		return 0
+	}
+	level := int(math.Ceil(float64(plays) / float64(busiest) * float64(levels-1)))
+	if level < 1 {
+		level = 1
+	}
+	return level
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(time.Local).Format("2006-01-02")
+}
+
+func formatMinutes(minutes float64) string {
+	return fmt.Sprintf("%.0f", minutes)
+}
+
+func formatHours(minutes float64) string {
+	return fmt.Sprintf("%.1f hours", minutes/60)
+}
+
+func formatStreak(streak Streak) string {
+	if streak.Days == 0 {
+		return "none"
+	}
+	if streak.Days == 1 {
+		return fmt.Sprintf("1 day, %s", formatDate(streak.Start))
+	}
+	return fmt.Sprintf("%d days, %s to %s", streak.Days, formatDate(streak.Start), formatDate(streak.End))
+}
+
+func percentOf(count Count, counts []Count) float64 {
+	busiest := busiestCount(counts).Plays
+	if busiest == 0 {
+		return 0
+	}
+	return float64(count.Plays) / float64(busiest) * 100
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":    formatDate,
+	"minutes": formatMinutes,
+	"hours":   formatHours,
+	"streak":  formatStreak,
+	"percent": percentOf,
+	"rate": func(rate float64) string {
+		return fmt.Sprintf("%.0f%%", rate*100)
+	},
+	"inc": func(i int) int {
+		return i + 1
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Listening report {{date .From}} to {{date .To}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; background: #121212; color: #e8e8e8; margin: 2em auto; max-width: 960px; padding: 0 1em; }
+h1, h2 { font-weight: 600; }
+h2 { margin-top: 2em; border-bottom: 1px solid #333; padding-bottom: .3em; }
+.summary { display: grid; grid-template-columns: repeat(auto-fill, minmax(180px, 1fr)); gap: 1em; }
+.stat { background: #1e1e1e; border-radius: 8px; padding: 1em; }
+.stat .value { font-size: 1.6em; font-weight: 600; color: #1db954; }
+.stat .label { color: #a0a0a0; font-size: .9em; }
+table { border-collapse: collapse; width: 100%; }
+td, th { padding: .35em .5em; text-align: left; }
+tr:nth-child(even) td { background: #1a1a1a; }
+.num { text-align: right; font-variant-numeric: tabular-nums; }
+.detail { color: #a0a0a0; }
+.chart td.bar { width: 70%; }
+.chart .fill { background: #1db954; height: 1em; border-radius: 2px; }
+.heatmap td { width: 3.5%; height: 1.4em; padding: 0; border: 1px solid #121212; }
+.heatmap th { font-weight: normal; color: #a0a0a0; font-size: .8em; }
+.l0 { background: #1e1e1e; } .l1 { background: #0e4a26; } .l2 { background: #137a3b; } .l3 { background: #19a34d; } .l4 { background: #1ed760; }
+</style>
+</head>
+<body>
+<h1>Listening report</h1>
+<p>{{date .From}} to {{date .To}}</p>
+
+<div class="summary">
+<div class="stat"><div class="value">{{.Plays}}</div><div class="label">plays</div></div>
+<div class="stat"><div class="value">{{hours .Minutes}}</div><div class="label">listened</div></div>
+<div class="stat"><div class="value">{{.ActiveDays}}</div><div class="label">active days</div></div>
+<div class="stat"><div class="value">{{.UniqueTracks}}</div><div class="label">unique tracks</div></div>
+<div class="stat"><div class="value">{{.UniqueArtists}}</div><div class="label">unique artists</div></div>
+<div class="stat"><div class="value">{{.UniqueAlbums}}</div><div class="label">unique albums</div></div>
+<div class="stat"><div class="value">{{.Discovery.NewTracks}}</div><div class="label">new tracks ({{rate .Discovery.TrackRate}})</div></div>
+<div class="stat"><div class="value">{{.Discovery.NewArtists}}</div><div class="label">new artists ({{rate .Discovery.ArtistRate}})</div></div>
+<div class="stat"><div class="value">{{.LongestStreak.Days}}</div><div class="label">longest streak in days</div></div>
+<div class="stat"><div class="value">{{.CurrentStreak.Days}}</div><div class="label">current streak in days</div></div>
+</div>
+{{if .BusiestDay.Plays}}<p>Busiest day: {{.BusiestDay.Name}} with {{.BusiestDay.Plays}} plays. Longest streak: {{streak .LongestStreak}}.</p>{{end}}
+
+{{define "counts"}}<table>
+<tr><th>#</th><th>Name</th><th class="num">Plays</th><th class="num">Minutes</th></tr>
+{{range $i, $c := .}}<tr><td>{{inc $i}}</td><td>{{$c.Name}}{{if $c.Detail}} <span class="detail">{{$c.Detail}}</span>{{end}}</td><td class="num">{{$c.Plays}}</td><td class="num">{{minutes $c.Minutes}}</td></tr>
+{{end}}</table>{{end}}
+
+{{define "chart"}}<table class="chart">
+{{$all := .}}{{range .}}<tr><td>{{.Name}}</td><td class="bar"><div class="fill" style="width: {{percent . $all}}%"></div></td><td class="num">{{.Plays}}</td></tr>
+{{end}}</table>{{end}}
+
+<h2>Top artists</h2>
+{{template "counts" .Artists}}
+<h2>Top albums</h2>
+{{template "counts" .Albums}}
+<h2>Top tracks</h2>
+{{template "counts" .Tracks}}
+{{if .Genres}}<h2>Top genres</h2>
+{{template "counts" .Genres}}{{end}}
+
+<h2>When you listen</h2>
+<table class="heatmap">
+<tr><th></th>{{range $i, $c := .HourChart}}<th>{{$i}}</th>{{end}}</tr>
+{{range .Heatmap}}<tr><th>{{.Day}}</th>{{range .Cells}}<td class="l{{.Level}}" title="{{.Plays}} plays"></td>{{end}}</tr>
+{{end}}</table>
+
+<h2>By hour</h2>
+{{template "chart" .HourChart}}
+<h2>By weekday</h2>
+{{template "chart" .WeekdayChart}}
+{{if .Months}}<h2>By month</h2>
+{{template "chart" .Months}}{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,255 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwseger/spotify-cli/history"
+
+	"github.com/pkg/errors"
+)
+
+const dayFormat = "2006-01-02"
+
+// Build works out a report over the plays in [from, to), either of which can
+// be zero to leave that end open. Days and hours are in the local time zone.
+// Minutes assume every play ran for the whole track, since Spotify doesn't say
+// how much of it was listened to. Genres are only counted when lookup is set.
+func Build(store *history.Store, from, to time.Time, top int, lookup GenreLookup) (*Report, error) {
+	seenTracks := map[string]bool{}
+	seenArtists := map[string]bool{}
+	if !from.IsZero() {
+		if err := store.Read(time.Time{}, from, func(play history.Play) error {
+			seenTracks[play.TrackID] = true
+			for _, artist := range play.Artists {
+				seenArtists[artist.ID] = true
+			}
+			return nil
+		}); err != nil {
+			return nil, errors.WithMessage(err, "Failed to read earlier history")
+		}
+	}
+
+	report := &Report{From: from}
+	if !to.IsZero() {
+		// To is the last moment covered, so a range ending at midnight shows the day before
+		report.To = to.Add(-time.Nanosecond)
+	}
+	artists := counter{}
+	albums := counter{}
+	tracks := counter{}
+	days := counter{}
+	months := counter{}
+	uniqueTracks := map[string]bool{}
+	uniqueArtists := map[string]bool{}
+	uniqueAlbums := map[string]bool{}
+	var first, last time.Time
+
+	if err := store.Read(from, to, func(play history.Play) error {
+		minutes := float64(play.DurationMS) / float64(time.Minute/time.Millisecond)
+		local := play.PlayedAt.In(time.Local)
+		if first.IsZero() {
+			first = play.PlayedAt
+		}
+		last = play.PlayedAt
+
+		report.Plays++
+		report.Minutes += minutes
+
+		names := make([]string, len(play.Artists))
+		for i, artist := range play.Artists {
+			names[i] = artist.Name
+			artists.add(artist.ID, artist.Name, "", minutes)
+			uniqueArtists[artist.ID] = true
+			if !seenArtists[artist.ID] {
+				seenArtists[artist.ID] = true
+				report.Discovery.NewArtists++
+			}
+		}
+		tracks.add(play.TrackID, play.TrackName, strings.Join(names, ", "), minutes)
+		uniqueTracks[play.TrackID] = true
+		if !seenTracks[play.TrackID] {
+			seenTracks[play.TrackID] = true
+			report.Discovery.NewTracks++
+		}
+		if play.AlbumID != "" {
+			albumArtist := ""
+			if len(names) > 0 {
+				albumArtist = names[0]
+			}
+			albums.add(play.AlbumID, play.AlbumName, albumArtist, minutes)
+			uniqueAlbums[play.AlbumID] = true
+		}
+
+		report.Hours[local.Hour()].Plays++
+		report.Hours[local.Hour()].Minutes += minutes
+		report.Weekdays[local.Weekday()].Plays++
+		report.Weekdays[local.Weekday()].Minutes += minutes
+		report.Heatmap[local.Weekday()][local.Hour()]++
+		days.add(local.Format(dayFormat), local.Format(dayFormat), "", minutes)
+		months.add(local.Format("2006-01"), local.Format("January 2006"), "", minutes)
+		return nil
+	}); err != nil {
+		return nil, errors.WithMessage(err, "Failed to read history")
+	}
+
+	if report.From.IsZero() {
+		report.From = first
+	}
+	if report.To.IsZero() {
+		report.To = last
+	}
+	report.UniqueTracks = len(uniqueTracks)
+	report.UniqueArtists = len(uniqueArtists)
+	report.UniqueAlbums = len(uniqueAlbums)
+	report.ActiveDays = len(days)
+	if report.UniqueTracks > 0 {
+		report.Discovery.TrackRate = float64(report.Discovery.NewTracks) / float64(report.UniqueTracks)
+	}
+	if report.UniqueArtists > 0 {
+		report.Discovery.ArtistRate = float64(report.Discovery.NewArtists) / float64(report.UniqueArtists)
+	}
+	for i := range report.Hours {
+		report.Hours[i].Name = time.Date(2000, 1, 1, i, 0, 0, 0, time.UTC).Format("15:00")
+	}
+	for i := range report.Weekdays {
+		report.Weekdays[i].Name = time.Weekday(i).String()
+	}
+
+	if lookup != nil && len(artists) > 0 {
+		genres, err := genreCounts(artists, lookup)
+		if err != nil {
+			return nil, err
+		}
+		report.Genres = genres.top(top)
+	}
+	report.Artists = artists.top(top)
+	report.Albums = albums.top(top)
+	report.Tracks = tracks.top(top)
+
+	byDay := days.top(1)
+	if len(byDay) > 0 {
+		report.BusiestDay = byDay[0]
+	}
+	report.Months = months.sorted()
+	report.LongestStreak, report.CurrentStreak = streaks(days, to)
+	return report, nil
+}
+
+// counter tallies plays and minutes by ID
+type counter map[string]*Count
+
+func (c counter) add(id, name, detail string, minutes float64) {
+	count, ok := c[id]
+	if !ok {
+		count = &Count{ID: id, Name: name, Detail: detail}
+		c[id] = count
+	}
+	count.Plays++
+	count.Minutes += minutes
+}
+
+// top returns the n most played, all of them when n isn't positive
+func (c counter) top(n int) []Count {
+	counts := make([]Count, 0, len(c))
+	for _, count := range c {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Plays != counts[j].Plays {
+			return counts[i].Plays > counts[j].Plays
+		}
+		if counts[i].Minutes != counts[j].Minutes {
+			return counts[i].Minutes > counts[j].Minutes
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// sorted returns every count ordered by ID
+func (c counter) sorted() []Count {
+	counts := make([]Count, 0, len(c))
+	for _, count := range c {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].ID < counts[j].ID
+	})
+	return counts
+}
+
+// genreCounts credits each genre with the plays of every artist tagged with it
+func genreCounts(artists counter, lookup GenreLookup) (counter, error) {
+	ids := make([]string, 0, len(artists))
+	for id := range artists {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	genres, err := lookup(ids)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to look up genres")
+	}
+
+	counts := counter{}
+	for id, artist := range artists {
+		for _, genre := range genres[id] {
+			count, ok := counts[genre]
+			if !ok {
+				count = &Count{ID: genre, Name: genre}
+				counts[genre] = count
+			}
+			count.Plays += artist.Plays
+			count.Minutes += artist.Minutes
+		}
+	}
+	return counts, nil
+}
+
+// streaks finds the longest run of active days and the run that is still
+// going at the end of the range, which counts as going if its last day was
+// the final day of the range or the one before it.
+func streaks(days counter, to time.Time) (Streak, Streak) {
+	var dates []time.Time
+	for day := range days {
+		date, err := time.ParseInLocation(dayFormat, day, time.Local)
+		if err == nil {
+			dates = append(dates, date)
+		}
+	}
+	if len(dates) == 0 {
+		return Streak{}, Streak{}
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	var longest, run Streak
+	for _, date := range dates {
+		if run.Days > 0 && date.Equal(run.End.AddDate(0, 0, 1)) {
+			run.End = date
+			run.Days++
+		} else {
+			run = Streak{Start: date, End: date, Days: 1}
+		}
+		if run.Days > longest.Days {
+			longest = run
+		}
+	}
+
+	end := time.Now()
+	if !to.IsZero() {
+		end = to.Add(-time.Nanosecond)
+	}
+	end = end.In(time.Local)
+	lastDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local)
+	if run.End.Equal(lastDay) || run.End.Equal(lastDay.AddDate(0, 0, -1)) {
+		return longest, run
+	}
+	return longest, Streak{}
+}
@@ -0,0 +1,63 @@
+package top
+
+// Compare ranks the short term items against the medium and long term ones.
+// An item's movement is measured against its medium term rank, or its long
+// term rank when it only shows up there; items in neither are new.
+func Compare(short, medium, long []Item) *Comparison {
+	shortRanks := ranks(short)
+	mediumRanks := ranks(medium)
+	longRanks := ranks(long)
+
+	var comparison Comparison
+	for i, item := range short {
+		entry := Entry{
+			Item:   item,
+			Short:  i + 1,
+			Medium: mediumRanks[item.ID],
+			Long:   longRanks[item.ID],
+		}
+
+		baseline := entry.Medium
+		if baseline == 0 {
+			baseline = entry.Long
+		}
+		entry.Change = baseline - entry.Short
+		switch {
+		case baseline == 0:
+			entry.Movement = MovementNew
+			entry.Change = 0
+		case entry.Change > 0:
+			entry.Movement = MovementUp
+		case entry.Change < 0:
+			entry.Movement = MovementDown
+		default:
+			entry.Movement = MovementSame
+		}
+		comparison.Entries = append(comparison.Entries, entry)
+	}
+
+	seen := map[string]bool{}
+	for _, list := range [][]Item{medium, long} {
+		for _, item := range list {
+			if shortRanks[item.ID] != 0 || seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			comparison.Dropped = append(comparison.Dropped, Entry{
+				Item:     item,
+				Medium:   mediumRanks[item.ID],
+				Long:     longRanks[item.ID],
+				Movement: MovementDropped,
+			})
+		}
+	}
+	return &comparison
+}
+
+func ranks(items []Item) map[string]int {
+	ranks := map[string]int{}
+	for i, item := range items {
+		ranks[item.ID] = i + 1
+	}
+	return ranks
+}
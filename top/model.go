@@ -0,0 +1,37 @@
+package top
+
+// Item is anything that can be ranked, an artist or a track
+type Item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+// Movements
+const (
+	MovementNew     = "new"
+	MovementUp      = "up"
+	MovementDown    = "down"
+	MovementSame    = "same"
+	MovementDropped = "dropped"
+)
+
+// Entry is an item's rank in each time range. A rank of 0 means the item
+// isn't in that range's list.
+type Entry struct {
+	Item     Item   `json:"item"`
+	Short    int    `json:"short_term"`
+	Medium   int    `json:"medium_term"`
+	Long     int    `json:"long_term"`
+	Movement string `json:"movement"`
+	// Change is how many places the item climbed (positive) or fell (negative)
+	Change int `json:"change"`
+}
+
+// Comparison -
+type Comparison struct {
+	// Entries are the short term items, in rank order
+	Entries []Entry `json:"entries"`
+	// Dropped are medium or long term items that fell out of the short term list
+	Dropped []Entry `json:"dropped"`
+}